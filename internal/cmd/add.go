@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"devctl/internal/config"
+	"devctl/internal/plan"
+	"devctl/pkg/cmdutil"
+	"devctl/pkg/pkgmgr"
+	"devctl/pkg/version"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdAdd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <manager>:<name>[@version]...",
+		Short: "Install packages and start tracking them",
+		Long: `Installs the given packages with their package manager and adds them to the configuration file, e.g. 'devctl add brew:jq@1.7.1 scoop:git'.
+
+The version is a version constraint such as "1.7.1" or "^1.6" and may be left out to accept any version. Packages that are already installed with a matching version are only tracked. For package names that contain "@", end the argument with "@" or a version, e.g. 'brew:node@20@'.
+
+If any package fails to install, the packages installed by this command are removed again and the configuration file is left untouched.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			pkgs := make([]config.PackageConfig, 0, len(args))
+			for _, arg := range args {
+				pkg, err := parsePackageArg(arg, true)
+				if err != nil {
+					return cmdutil.FlagErrorWrap(err)
+				}
+				if pkg.InstalledBy == "" {
+					return cmdutil.FlagErrorf("%s: missing package manager, use <manager>:<name>", arg)
+				}
+				pkgs = append(pkgs, pkg)
+			}
+			return runAdd(cfg, pkgs)
+		},
+	}
+
+	return cmd
+}
+
+func runAdd(cfg *config.Config, pkgs []config.PackageConfig) error {
+	unlock, err := lockConfig(cfg)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for _, pkg := range pkgs {
+		if _, ok := cfg.PackageManagers[pkg.InstalledBy]; !ok {
+			return fmt.Errorf("package manager %s not configured", pkg.InstalledBy)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	installed, err := listInstalled(ctx, cfg, usedManagerTypes(pkgs))
+	if err != nil {
+		return err
+	}
+
+	p := plan.Build(pkgs, installed, plan.Options{})
+	return applyPlan(ctx, cfg, p, defaultJobs, true)
+}
+
+// parsePackageArg parses a package given on the command line as
+// [<manager>:]<name>[@version]. The version is only accepted if withVersion
+// is set and is split off at the last "@", so names that contain "@" need a
+// trailing "@".
+func parsePackageArg(arg string, withVersion bool) (config.PackageConfig, error) {
+	var pkg config.PackageConfig
+
+	name := arg
+	if i := strings.Index(name, ":"); i >= 0 {
+		pkg.InstalledBy = pkgmgr.ManagerType(name[:i])
+		name = name[i+1:]
+		if pkg.InstalledBy == "" {
+			return pkg, fmt.Errorf("%s: missing package manager before ':'", arg)
+		}
+	}
+	if withVersion {
+		if i := strings.LastIndex(name, "@"); i > 0 {
+			name, pkg.Version = name[:i], name[i+1:]
+		}
+		if _, err := version.ParseConstraint(pkg.Version); err != nil {
+			return pkg, fmt.Errorf("%s: %w", arg, err)
+		}
+	}
+	if name == "" {
+		return pkg, fmt.Errorf("%s: missing package name", arg)
+	}
+
+	pkg.Name = name
+	return pkg, nil
+}
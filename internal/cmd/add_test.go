@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"devctl/internal/config"
+	"devctl/pkg/pkgmgr"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePackageArg(t *testing.T) {
+	tests := []struct {
+		arg         string
+		withVersion bool
+		want        config.PackageConfig
+		wantErr     string
+	}{
+		{arg: "git", withVersion: true, want: config.PackageConfig{Name: "git"}},
+		{arg: "brew:git", withVersion: true, want: config.PackageConfig{Name: "git", InstalledBy: pkgmgr.ManagerTypeBrew}},
+		{arg: "git@2.43.0", withVersion: true, want: config.PackageConfig{Name: "git", Version: "2.43.0"}},
+		{arg: "git@^1.6", withVersion: true, want: config.PackageConfig{Name: "git", Version: "^1.6"}},
+		{arg: "git@2.43.0", withVersion: false, want: config.PackageConfig{Name: "git@2.43.0"}},
+		{arg: "node@20@", withVersion: true, want: config.PackageConfig{Name: "node@20"}},
+		{arg: "brew:node@20@20.11.1", withVersion: true, want: config.PackageConfig{Name: "node@20", Version: "20.11.1", InstalledBy: pkgmgr.ManagerTypeBrew}},
+		{arg: "scoop:versions/python", withVersion: false, want: config.PackageConfig{Name: "versions/python", InstalledBy: pkgmgr.ManagerTypeScoop}},
+		{arg: ":git", withVersion: true, wantErr: "missing package manager before ':'"},
+		{arg: "brew:", withVersion: true, wantErr: "missing package name"},
+		{arg: "git@>=", withVersion: true, wantErr: "invalid version constraint"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := parsePackageArg(tt.arg, tt.withVersion)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
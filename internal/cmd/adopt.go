@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"devctl/internal/config"
+	"devctl/internal/inventory"
+	"devctl/internal/ui"
+	"devctl/pkg/cmdutil"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdAdopt(cfg *config.Config) *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "adopt [<pattern>...]",
+		Short: "Start tracking packages that are already installed",
+		Long: `Lists the installed packages of every configured package manager that are not tracked yet and adds the chosen ones to the configuration file with their installed versions. Nothing is installed or removed.
+
+Without arguments, the packages are picked interactively. With --all, every untracked package is adopted except packages the package manager reports as only installed as a dependency. Glob patterns such as 'node*' adopt the untracked packages whose name matches any of them.`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			if all && len(args) > 0 {
+				return cmdutil.FlagErrorf("cannot use --all with patterns")
+			}
+			for _, pattern := range args {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return cmdutil.FlagErrorf("invalid pattern %q: %v", pattern, err)
+				}
+			}
+			return runAdopt(cfg, args, all)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "adopt all untracked packages that are not dependencies")
+
+	return cmd
+}
+
+func runAdopt(cfg *config.Config, patterns []string, all bool) error {
+	unlock, err := lockConfig(cfg)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if len(cfg.PackageManagers) == 0 {
+		return fmt.Errorf("no package managers configured, run 'devctl init' first")
+	}
+
+	interactive := !all && len(patterns) == 0
+	if interactive && !term.IsTerminal(os.Stdin.Fd()) {
+		return cmdutil.FlagErrorf("pass --all or name patterns when not running in a terminal")
+	}
+
+	installed, err := listInstalled(context.Background(), cfg, managerTypes(cfg))
+	if err != nil {
+		return err
+	}
+
+	untracked := inventory.Compare(cfg.Packages, installed).Untracked
+	if len(untracked) == 0 {
+		fmt.Println("No untracked packages to adopt")
+		return nil
+	}
+
+	var adopted []config.PackageConfig
+	switch {
+	case interactive:
+		options := make([]ui.SelectOption, 0, len(untracked))
+		for _, e := range untracked {
+			name := e.Name
+			if e.Repository != "" {
+				name = e.Repository + "/" + e.Name
+			}
+			label := fmt.Sprintf("%s %s (%s)", e.InstalledBy, name, e.Installed)
+			if live := inventory.FindFrom(installed[e.InstalledBy], e.Repository, e.Name); live != nil && live.Dependency {
+				label += " [dependency]"
+			}
+			options = append(options, ui.SelectOption{Key: adoptKey(e), Label: label})
+		}
+		keys, err := ui.SelectPackages("Select the packages to track", options)
+		if err != nil {
+			return fmt.Errorf("failed to get package selection: %w", err)
+		}
+		for _, e := range untracked {
+			if slices.Contains(keys, adoptKey(e)) {
+				adopted = append(adopted, adoptedPackage(e))
+			}
+		}
+	case all:
+		for _, e := range untracked {
+			if live := inventory.FindFrom(installed[e.InstalledBy], e.Repository, e.Name); live != nil && live.Dependency {
+				continue
+			}
+			adopted = append(adopted, adoptedPackage(e))
+		}
+	default:
+		for _, e := range untracked {
+			if slices.ContainsFunc(patterns, func(pattern string) bool {
+				ok, _ := path.Match(pattern, e.Name)
+				return ok
+			}) {
+				adopted = append(adopted, adoptedPackage(e))
+			}
+		}
+	}
+
+	if len(adopted) == 0 {
+		if len(patterns) > 0 {
+			fmt.Println("No untracked packages match the given patterns")
+		} else {
+			fmt.Println("No packages selected")
+		}
+		return nil
+	}
+
+	cfg.Packages = config.MergePackages(cfg.Packages, adopted)
+	if err := saveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	out := ui.NewDefaultOutput()
+	for _, pkg := range adopted {
+		out.Success(fmt.Sprintf("Tracking %s %s %s", pkg.InstalledBy, pkg.Name, pkg.Version))
+	}
+	return nil
+}
+
+func adoptKey(e inventory.Entry) string {
+	return string(e.InstalledBy) + ":" + e.Repository + "/" + e.Name
+}
+
+func adoptedPackage(e inventory.Entry) config.PackageConfig {
+	return config.PackageConfig{
+		Name:        e.Name,
+		Version:     e.Installed,
+		InstalledBy: e.InstalledBy,
+		Repository:  e.Repository,
+	}
+}
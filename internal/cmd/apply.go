@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"context"
+	"devctl/internal/config"
+	"devctl/internal/journal"
+	"devctl/internal/plan"
+	"devctl/internal/ui"
+	"devctl/pkg/cmdutil"
+	"devctl/pkg/pkgmgr"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdApply(cfg *config.Config) *cobra.Command {
+	var planFile string
+	var prune bool
+	var jobs int
+
+	cmd := &cobra.Command{
+		Use:   "apply [<file>]",
+		Short: "Apply a manifest or a saved plan",
+		Long: `Plans a manifest against the installed packages and executes the plan.
+
+With --plan, executes a plan saved by 'devctl plan -o' exactly as it was reviewed. The plan is rejected if the installed packages changed since it was made.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if planFile != "" && len(args) > 0 {
+				return cmdutil.FlagErrorf("cannot use a manifest file and --plan together")
+			}
+			if planFile == "" && len(args) == 0 {
+				return cmdutil.FlagErrorf("a manifest file or --plan is required")
+			}
+			if planFile != "" && prune {
+				return cmdutil.FlagErrorf("cannot use --prune with --plan")
+			}
+			if jobs < 1 {
+				return cmdutil.FlagErrorf("--jobs must be at least 1")
+			}
+
+			filePath := ""
+			if len(args) > 0 {
+				filePath = args[0]
+			}
+			return runApply(cfg, filePath, planFile, prune, jobs)
+		},
+	}
+
+	cmd.Flags().StringVar(&planFile, "plan", "", "execute a saved plan file")
+	cmd.Flags().BoolVar(&prune, "prune", false, "remove tracked packages that are not in the manifest")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", defaultJobs, "number of package managers to run concurrently")
+
+	return cmd
+}
+
+func runApply(cfg *config.Config, filePath, planFile string, prune bool, jobs int) error {
+	unlock, err := lockConfig(cfg)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var p *plan.Plan
+	if planFile != "" {
+		p, err = loadPlan(ctx, cfg, planFile)
+	} else {
+		p, err = buildPlan(ctx, cfg, filePath, prune, false)
+	}
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		fmt.Println("No valid packages to apply")
+		return nil
+	}
+
+	printPlan(ui.NewDefaultOutput(), p)
+	return applyPlan(ctx, cfg, p, jobs, false)
+}
+
+// loadPlan loads a saved plan and verifies it still matches the installed packages.
+func loadPlan(ctx context.Context, cfg *config.Config, planFile string) (*plan.Plan, error) {
+	p, err := plan.Load(planFile)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range p.ManagerTypes() {
+		if _, ok := cfg.PackageManagers[t]; !ok {
+			return nil, fmt.Errorf("package manager %s not configured", t)
+		}
+	}
+
+	installed, err := listInstalled(ctx, cfg, p.ManagerTypes())
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckCurrent(installed); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// defaultJobs is the default number of package managers applied concurrently.
+const defaultJobs = 4
+
+// applyPlan executes the actions of a plan and records the results in the configuration.
+// Each package manager runs in its own worker, with at most jobs workers at a time.
+// Actions of the same manager are executed one batch after another.
+//
+// If atomic is set, the actions are journaled in the data directory and the
+// first failure stops the run and undoes all actions taken, leaving the
+// configuration untouched.
+func applyPlan(ctx context.Context, cfg *config.Config, p *plan.Plan, jobs int, atomic bool) error {
+	if len(p.Actions) == 0 {
+		fmt.Println("Nothing to apply")
+		return nil
+	}
+
+	var j *journal.Journal
+	if atomic {
+		path := journal.Path(cfg.DataDir)
+		if cfg.DryRun {
+			recorder.RecordWrite(path)
+		} else {
+			var err error
+			j, err = journal.Create(path, p, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	packageInfos := make([]ui.PackageInfo, len(p.Actions))
+	for i, a := range p.Actions {
+		v := a.Version
+		if a.Type == plan.ActionRemove {
+			v = a.CurrentVersion
+		}
+		packageInfos[i] = ui.PackageInfo{
+			Name:    a.Name,
+			Version: v,
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	failed := func() {}
+	if atomic {
+		failed = cancel
+	}
+
+	succeeded := make([]bool, len(p.Actions))
+
+	var tracker = ui.NewProgressTracker(packageInfos)
+	tracker.OnInterrupt(cancel)
+	tracker.Start()
+
+	for i, a := range p.Actions {
+		if a.Type == plan.ActionNoop {
+			tracker.SkipPackage(i, "already installed")
+			succeeded[i] = true
+		}
+	}
+
+	batches := map[pkgmgr.ManagerType][]plan.Batch{}
+	for _, b := range p.Batches() {
+		batches[b.InstalledBy] = append(batches[b.InstalledBy], b)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(jobs, 1))
+	for _, t := range p.ManagerTypes() {
+		if len(batches[t]) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			applyBatches(ctx, cfg, p, batches[t], tracker, succeeded, j, failed)
+		}()
+	}
+	wg.Wait()
+
+	tracker.Stop()
+
+	if atomic {
+		if n := countFailed(succeeded); n > 0 {
+			if j == nil {
+				return fmt.Errorf("%d action(s) failed", n)
+			}
+			if err := undoJournal(context.WithoutCancel(ctx), cfg, j); err != nil {
+				return fmt.Errorf("%d action(s) failed: %w", n, err)
+			}
+			return fmt.Errorf("%d action(s) failed, all changes were rolled back", n)
+		}
+	}
+
+	trackResults(cfg, p.Actions, succeeded)
+
+	if err := saveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	if j != nil {
+		if err := j.Remove(); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("interrupted: %w", err)
+	}
+
+	return nil
+}
+
+// trackResults records the actions that succeeded in the configuration.
+func trackResults(cfg *config.Config, actions []plan.Action, succeeded []bool) {
+	var applied, removed []config.PackageConfig
+	for i, a := range actions {
+		if !succeeded[i] {
+			continue
+		}
+		if a.Type == plan.ActionRemove {
+			removed = append(removed, a.Package())
+		} else {
+			applied = append(applied, a.Package())
+		}
+	}
+
+	cfg.Packages = config.MergePackages(cfg.Packages, applied)
+	cfg.Packages = config.RemovePackages(cfg.Packages, removed)
+}
+
+func countFailed(succeeded []bool) int {
+	n := 0
+	for _, ok := range succeeded {
+		if !ok {
+			n++
+		}
+	}
+	return n
+}
+
+// applyBatches executes the batches of a single package manager in order and
+// marks the actions that took effect in succeeded. Batches that have not
+// started when ctx is cancelled are skipped. If j is not nil, the batches are
+// recorded in it. failed is called whenever an action fails.
+func applyBatches(ctx context.Context, cfg *config.Config, p *plan.Plan, batches []plan.Batch, tracker *ui.ProgressTracker, succeeded []bool, j *journal.Journal, failed func()) {
+	fail := func(i int, err error) {
+		tracker.FailPackage(i, err)
+		failed()
+	}
+	complete := func(i int) {
+		tracker.CompletePackage(i, actionNote(p.Actions[i].Type))
+		succeeded[i] = true
+		if j != nil {
+			if err := j.Done(i); err != nil {
+				slog.Error("failed to update journal", "err", err)
+			}
+		}
+	}
+
+	var mgr pkgmgr.Manager
+	for _, b := range batches {
+		if ctx.Err() != nil {
+			for _, i := range b.Indexes {
+				tracker.SkipPackage(i, "cancelled")
+			}
+			continue
+		}
+
+		for _, i := range b.Indexes {
+			tracker.StartPackage(i)
+		}
+
+		if mgr == nil {
+			var err error
+			mgr, err = getManager(cfg, b.InstalledBy)
+			if err != nil {
+				for _, i := range b.Indexes {
+					fail(i, err)
+				}
+				continue
+			}
+		}
+
+		if j != nil {
+			if err := j.Start(b.Indexes...); err != nil {
+				for _, i := range b.Indexes {
+					fail(i, err)
+				}
+				continue
+			}
+		}
+
+		err := b.Execute(ctx, mgr, p.Actions)
+		if err == nil {
+			for _, i := range b.Indexes {
+				complete(i)
+			}
+			continue
+		}
+		if len(b.Indexes) == 1 {
+			fail(b.Indexes[0], err)
+			continue
+		}
+
+		// A batch can fail part way through. List the installed packages
+		// again to find out which actions took effect anyway.
+		installed, listErr := mgr.List(context.WithoutCancel(ctx))
+		for _, i := range b.Indexes {
+			if listErr == nil && p.Actions[i].Satisfied(installed) {
+				complete(i)
+			} else {
+				fail(i, err)
+			}
+		}
+	}
+}
+
+func actionNote(t plan.ActionType) string {
+	switch t {
+	case plan.ActionInstall:
+		return "installed"
+	case plan.ActionUpgrade:
+		return "upgraded"
+	case plan.ActionDowngrade:
+		return "downgraded"
+	case plan.ActionReinstall:
+		return "reinstalled"
+	case plan.ActionRemove:
+		return "removed"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"devctl/internal/config"
+	"devctl/internal/plan"
+	"devctl/internal/ui"
+	"devctl/pkg/pkgmgr"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const managerTypeFake pkgmgr.ManagerType = "fake"
+
+// fake is the manager returned for managerTypeFake. Tests replace it.
+var fake = &fakeManager{}
+
+func init() {
+	pkgmgr.Register(pkgmgr.Descriptor{
+		Type: managerTypeFake,
+		New:  func(pkgmgr.ManagerConfig) (pkgmgr.Manager, error) { return fake, nil },
+	})
+}
+
+// fakeManager keeps the installed packages in memory. Installs of the names
+// in broken fail, the other names of the same call are still installed.
+type fakeManager struct {
+	installed []pkgmgr.Package
+	broken    []string
+}
+
+func (m *fakeManager) Install(_ context.Context, names ...string) error {
+	var failed []string
+	for _, name := range names {
+		v := ""
+		if i := strings.LastIndex(name, "@"); i > 0 {
+			name, v = name[:i], name[i+1:]
+		}
+		if slices.Contains(m.broken, name) {
+			failed = append(failed, name)
+			continue
+		}
+		m.installed = append(m.installed, pkgmgr.Package{Name: name, Version: v})
+	}
+	if len(failed) > 0 {
+		return errors.New("failed to install " + strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (m *fakeManager) Uninstall(_ context.Context, names ...string) error {
+	m.installed = slices.DeleteFunc(m.installed, func(p pkgmgr.Package) bool {
+		return slices.Contains(names, p.Name)
+	})
+	return nil
+}
+
+func (m *fakeManager) List(context.Context) ([]pkgmgr.Package, error) {
+	return slices.Clone(m.installed), nil
+}
+
+func TestApplyBatches(t *testing.T) {
+	cfg := &config.Config{
+		PackageManagers: map[pkgmgr.ManagerType]config.PackageManagerConfig{
+			managerTypeFake: {ExecutablePath: "fake"},
+		},
+	}
+	desired := []config.PackageConfig{
+		{Name: "git", Version: "2.44.0", InstalledBy: managerTypeFake},
+		{Name: "jq", Version: "1.7.1", InstalledBy: managerTypeFake},
+		{Name: "wget", Version: "1.21.4", InstalledBy: managerTypeFake},
+	}
+
+	tests := []struct {
+		name   string
+		broken []string
+		want   []bool
+	}{
+		{name: "all installed", want: []bool{true, true, true}},
+		{name: "partially failed batch", broken: []string{"jq"}, want: []bool{true, false, true}},
+		{name: "failed batch", broken: []string{"git", "jq", "wget"}, want: []bool{false, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake = &fakeManager{broken: tt.broken}
+			p := plan.Build(desired, nil, plan.Options{})
+			batches := p.Batches()
+			require.Len(t, batches, 1)
+
+			tracker := ui.NewProgressTracker(make([]ui.PackageInfo, len(p.Actions)))
+			succeeded := make([]bool, len(p.Actions))
+			failures := 0
+			applyBatches(context.Background(), cfg, p, batches, tracker, succeeded, nil, func() { failures++ })
+
+			require.Equal(t, tt.want, succeeded)
+			require.Equal(t, len(tt.broken), failures)
+			require.Equal(t, len(tt.broken), tracker.GetFailedCount())
+		})
+	}
+}
+
+func TestApplyBatchesCancelled(t *testing.T) {
+	cfg := &config.Config{
+		PackageManagers: map[pkgmgr.ManagerType]config.PackageManagerConfig{
+			managerTypeFake: {ExecutablePath: "fake"},
+		},
+	}
+	fake = &fakeManager{}
+	p := plan.Build([]config.PackageConfig{{Name: "git", InstalledBy: managerTypeFake}}, nil, plan.Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	succeeded := make([]bool, len(p.Actions))
+	applyBatches(ctx, cfg, p, p.Batches(), ui.NewProgressTracker(make([]ui.PackageInfo, len(p.Actions))), succeeded, nil, func() {})
+
+	require.Equal(t, []bool{false}, succeeded)
+	require.Empty(t, fake.installed)
+}
@@ -1,37 +1,78 @@
 package cmd
 
 import (
+	"cmp"
+	"context"
 	"devctl/internal/config"
 	"devctl/internal/formats"
+	"devctl/internal/inventory"
 	"devctl/pkg/cmdutil"
+	"devctl/pkg/codec"
+	"devctl/pkg/pkgmgr"
 	"fmt"
+	"path"
 	"path/filepath"
 	"runtime"
+	"slices"
 
 	"github.com/spf13/cobra"
 )
 
+// exportFilter selects the packages written by export.
+type exportFilter struct {
+	// Managers limits the export to these package managers. Empty means all.
+	Managers []pkgmgr.ManagerType
+	// Exclude holds glob patterns of package names to leave out.
+	Exclude []string
+	// OnlyTracked leaves out installed packages that are not tracked.
+	OnlyTracked bool
+}
+
 func NewCmdExport(cfg *config.Config) *cobra.Command {
 	var outDir string
 	var outFile string
+	var format string
+	var fromSystem bool
+	var managers []string
+	var filter exportFilter
 
 	cmd := &cobra.Command{
 		Use:   "export",
-		Short: "Export installed packages from config",
-		Long:  `Export installed packages from the configuration file to a JSON file that can be used with 'devctl import'.`,
-		Args:  cobra.NoArgs,
+		Short: "Export tracked or installed packages to a manifest",
+		Long: `Export installed packages from the configuration file to a manifest that can be used with 'devctl import'.
+
+With --from-system, the installed packages of every configured package manager are listed and merged with the tracked packages, so packages installed outside devctl are exported too. Tracked packages keep their tracked version, the other packages are exported with their installed version. Packages the package manager reports as only installed as a dependency are marked with "dependency": true.
+
+The manifest is written as JSON, YAML or TOML. With -o, the format follows the file extension unless --format is given.`,
+		Args: cobra.NoArgs,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			return runExport(cfg, outDir, outFile)
+			if filter.OnlyTracked && !fromSystem {
+				return cmdutil.FlagErrorf("--only-tracked requires --from-system")
+			}
+			for _, m := range managers {
+				filter.Managers = append(filter.Managers, pkgmgr.ManagerType(m))
+			}
+			for _, pattern := range filter.Exclude {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return cmdutil.FlagErrorf("invalid --exclude pattern %q: %v", pattern, err)
+				}
+			}
+			return runExport(cfg, outDir, outFile, codec.Format(format), fromSystem, filter)
 		},
 	}
 
 	cmd.Flags().StringVarP(&outDir, "dir", "d", "", "output directory")
 	cmd.Flags().StringVarP(&outFile, "output", "o", "", "output file path")
+	cmd.Flags().StringVar(&format, "format", "", "output format: json, yaml or toml")
+	cmd.Flags().BoolVar(&fromSystem, "from-system", false, "export the installed packages merged with the tracked packages")
+	cmd.Flags().StringSliceVar(&managers, "manager", nil, "only export packages of these package managers")
+	cmd.Flags().StringSliceVar(&filter.Exclude, "exclude", nil, "leave out packages whose name matches these glob patterns")
+	cmd.Flags().BoolVar(&filter.OnlyTracked, "only-tracked", false, "with --from-system, leave out packages that are not tracked")
 
 	return cmd
 }
 
-func runExport(cfg *config.Config, outDir, outFile string) error {
+func runExport(cfg *config.Config, outDir, outFile string, format codec.Format, fromSystem bool, filter exportFilter) error {
 	if cfg == nil {
 		return fmt.Errorf("missing config")
 	}
@@ -40,9 +81,20 @@ func runExport(cfg *config.Config, outDir, outFile string) error {
 		return cmdutil.FlagErrorf("cannot use -d and -o together")
 	}
 
+	c := codec.JSON
+	if format != "" {
+		var err error
+		if c, err = codec.ForFormat(format); err != nil {
+			return cmdutil.FlagErrorf("%v", err)
+		}
+	}
+	if outFile != "" && format != "" && codec.ForPath(outFile) != c {
+		return cmdutil.FlagErrorf("output file %s does not match --format %s", outFile, format)
+	}
+
 	exportPath := outFile
 	if exportPath == "" {
-		fileName := fmt.Sprintf("%s-export.%s.json", config.AppName, runtime.GOOS)
+		fileName := fmt.Sprintf("%s-export.%s%s", config.AppName, runtime.GOOS, c.Extension())
 		dir := outDir
 		if dir == "" {
 			dir = "."
@@ -50,15 +102,33 @@ func runExport(cfg *config.Config, outDir, outFile string) error {
 		exportPath = filepath.Join(dir, fileName)
 	}
 
-	pkgs := make([]formats.PackageFormat, 0, len(cfg.Packages))
-	for _, p := range cfg.Packages {
-		pf := formats.FromConfig(p)
-		if pf.Name == "" || pf.Version == "" || pf.InstalledBy == "" {
-			continue
+	var pkgs []formats.PackageFormat
+	if fromSystem {
+		types := filter.Managers
+		if len(types) == 0 {
+			types = managerTypes(cfg)
+		}
+		for _, m := range types {
+			if _, ok := cfg.PackageManagers[m]; !ok {
+				return cmdutil.FlagErrorf("package manager %s is not configured", m)
+			}
+		}
+		installed, err := listInstalled(context.Background(), cfg, types)
+		if err != nil {
+			return err
+		}
+		pkgs = systemPackages(cfg.Packages, installed, filter.OnlyTracked)
+	} else {
+		pkgs = make([]formats.PackageFormat, 0, len(cfg.Packages))
+		for _, p := range cfg.Packages {
+			pkgs = append(pkgs, formats.FromConfig(p))
 		}
-		pkgs = append(pkgs, pf)
 	}
 
+	pkgs = slices.DeleteFunc(pkgs, func(pf formats.PackageFormat) bool {
+		return pf.Name == "" || pf.InstalledBy == "" || !filter.match(pf)
+	})
+
 	if len(pkgs) == 0 {
 		fmt.Println("No valid packages to export")
 		return nil
@@ -69,6 +139,11 @@ func runExport(cfg *config.Config, outDir, outFile string) error {
 		Packages: pkgs,
 	}
 
+	if cfg.DryRun {
+		recorder.RecordWrite(exportPath)
+		return nil
+	}
+
 	if err := formats.SaveManifestFile(exportPath, exportFile); err != nil {
 		return err
 	}
@@ -76,3 +151,53 @@ func runExport(cfg *config.Config, outDir, outFile string) error {
 	fmt.Printf("Exported to: %s\n", exportPath)
 	return nil
 }
+
+// match reports whether pf passes the manager and exclude filters.
+func (f exportFilter) match(pf formats.PackageFormat) bool {
+	if len(f.Managers) > 0 && !slices.Contains(f.Managers, pf.InstalledBy) {
+		return false
+	}
+	for _, pattern := range f.Exclude {
+		if ok, _ := path.Match(pattern, pf.Name); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// systemPackages merges the installed packages with the tracked packages of
+// the managers in installed, sorted by manager, repository and name. Tracked packages keep
+// their tracked version, even if they are missing; untracked packages get
+// their installed version unless onlyTracked is set.
+func systemPackages(tracked []config.PackageConfig, installed inventory.Installed, onlyTracked bool) []formats.PackageFormat {
+	var pkgs []formats.PackageFormat
+	for _, p := range tracked {
+		if _, ok := installed[p.InstalledBy]; !ok {
+			continue
+		}
+		pf := formats.FromConfig(p)
+		if live := inventory.FindFrom(installed[p.InstalledBy], p.Repository, p.Name); live != nil {
+			pf.Dependency = live.Dependency
+		}
+		pkgs = append(pkgs, pf)
+	}
+
+	if !onlyTracked {
+		report := inventory.Compare(tracked, installed)
+		for _, e := range report.Untracked {
+			live := inventory.FindFrom(installed[e.InstalledBy], e.Repository, e.Name)
+			pkgs = append(pkgs, formats.PackageFormat{
+				Name:        e.Name,
+				Version:     e.Installed,
+				InstalledBy: e.InstalledBy,
+				Repository:  e.Repository,
+				Dependency:  live != nil && live.Dependency,
+			})
+		}
+	}
+
+	slices.SortFunc(pkgs, func(a, b formats.PackageFormat) int {
+		return cmp.Or(cmp.Compare(a.InstalledBy, b.InstalledBy), cmp.Compare(a.Repository, b.Repository), cmp.Compare(a.Name, b.Name))
+	})
+	return pkgs
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"devctl/internal/config"
+	"devctl/internal/formats"
+	"devctl/internal/inventory"
+	"devctl/pkg/pkgmgr"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemPackages(t *testing.T) {
+	brew, scoop := pkgmgr.ManagerTypeBrew, pkgmgr.ManagerTypeScoop
+	tracked := []config.PackageConfig{
+		{Name: "git", Version: "^2.40", InstalledBy: brew},
+		{Name: "jq", Version: "1.7.1", InstalledBy: brew},
+		{Name: "7zip", Version: "23.01", InstalledBy: scoop},
+	}
+	installed := inventory.Installed{
+		brew: {
+			{Name: "wget", Version: "1.21.4"},
+			{Name: "git", Version: "2.44.0"},
+			{Name: "openssl@3", Version: "3.2.1", Dependency: true},
+		},
+	}
+
+	t.Run("with untracked packages", func(t *testing.T) {
+		require.Equal(t, []formats.PackageFormat{
+			{Name: "git", Version: "^2.40", InstalledBy: brew},
+			{Name: "jq", Version: "1.7.1", InstalledBy: brew},
+			{Name: "openssl@3", Version: "3.2.1", InstalledBy: brew, Dependency: true},
+			{Name: "wget", Version: "1.21.4", InstalledBy: brew},
+		}, systemPackages(tracked, installed, false))
+	})
+
+	t.Run("only tracked packages", func(t *testing.T) {
+		require.Equal(t, []formats.PackageFormat{
+			{Name: "git", Version: "^2.40", InstalledBy: brew},
+			{Name: "jq", Version: "1.7.1", InstalledBy: brew},
+		}, systemPackages(tracked, installed, true))
+	})
+}
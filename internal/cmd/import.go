@@ -3,171 +3,115 @@ package cmd
 import (
 	"context"
 	"devctl/internal/config"
+	"devctl/internal/dryrun"
 	"devctl/internal/formats"
-	"devctl/internal/ui"
+	"devctl/pkg/cmdutil"
 	"devctl/pkg/pkgmgr"
-	"devctl/pkg/pkgmgr/scoop"
-	"devctl/pkg/version"
 	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
 
 	"github.com/spf13/cobra"
 )
 
 func NewCmdImport(cfg *config.Config) *cobra.Command {
+	var jobs int
+	var frozen bool
+	var atomic bool
+
 	cmd := &cobra.Command{
 		Use:   "import <file>",
-		Short: "Import packages from JSON file",
-		Long:  `Import packages from a JSON configuration file and install them using the configured package managers.`,
-		Args:  cobra.ExactArgs(1),
+		Short: "Import packages from a JSON, YAML or TOML manifest",
+		Long: `Import packages from a manifest and install them using the configured package managers. The format of the manifest, JSON, YAML or TOML, is detected from the file extension.
+
+The file is either a manifest written by 'devctl export' for a single platform, or a cross-platform manifest whose "tools" are mapped to a package per platform. Tools without a mapping for the current platform are skipped.
+
+With --atomic, every action is journaled in the data directory and the first failure undoes all actions taken so far, leaving the configuration untouched. If devctl is killed during an atomic import, run 'devctl recover' to finish or undo it.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(_ *cobra.Command, args []string) error {
-			return runImport(cfg, args[0])
+			if jobs < 1 {
+				return cmdutil.FlagErrorf("--jobs must be at least 1")
+			}
+			return runImport(cfg, args[0], jobs, frozen, atomic)
 		},
 	}
+
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", defaultJobs, "number of package managers to run concurrently")
+	cmd.Flags().BoolVar(&frozen, "frozen", false, fmt.Sprintf("install the exact versions of the manifest's %s lockfile and fail if it is stale", formats.LockFileSuffix))
+	cmd.Flags().BoolVar(&atomic, "atomic", false, "undo all changes if any package fails")
+
 	return cmd
 }
 
-func runImport(cfg *config.Config, filePath string) error {
-	importFile, err := formats.LoadManifestFile(filePath)
+func runImport(cfg *config.Config, filePath string, jobs int, frozen, atomic bool) error {
+	unlock, err := lockConfig(cfg)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	var validPackages []config.PackageConfig
-	for _, pkg := range importFile.Packages {
-		if pkg.InstalledBy != pkgmgr.ManagerTypeScoop {
-			continue
-		}
-		if _, ok := cfg.PackageManagers[pkg.InstalledBy]; !ok {
-			return fmt.Errorf("package manager %s not configured", pkg.InstalledBy)
-		}
-		validPackages = append(validPackages, pkg.ToConfig())
-	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	if len(validPackages) == 0 {
+	p, err := buildPlan(ctx, cfg, filePath, false, frozen)
+	if err != nil {
+		return err
+	}
+	if p == nil {
 		fmt.Println("No valid packages to import")
 		return nil
 	}
 
-	ctx := context.Background()
-	var successfulPackages []config.PackageConfig
+	return applyPlan(ctx, cfg, p, jobs, atomic)
+}
 
-	packageInfos := make([]ui.PackageInfo, len(validPackages))
-	for i, pkg := range validPackages {
-		packageInfos[i] = ui.PackageInfo{
-			Name:    pkg.Name,
-			Version: pkg.Version,
-		}
+// loadDesiredPackages loads a manifest and returns the packages that can be
+// installed on the current platform.
+func loadDesiredPackages(cfg *config.Config, filePath string) ([]config.PackageConfig, error) {
+	importFile, err := formats.LoadManifestFile(filePath)
+	if err != nil {
+		return nil, err
 	}
 
-	var tracker = ui.NewProgressTracker(packageInfos)
-	tracker.Start()
-
-	for i, pkg := range validPackages {
-		tracker.StartPackage(i)
-
-		if pkg.InstalledBy == "" {
-			tracker.FailPackage(i, fmt.Errorf("manager type is required"))
-			continue
-		}
-
-		mgrConfig := cfg.PackageManagers[pkg.InstalledBy]
-		mgr, err := getManager(pkg.InstalledBy, mgrConfig)
-		if err != nil {
-			tracker.FailPackage(i, err)
-			continue
-		}
-
-		status, note, err := processPackage(ctx, mgr, pkg)
-		if err != nil {
-			tracker.FailPackage(i, err)
+	var validPackages []config.PackageConfig
+	for _, pkg := range importFile.Packages {
+		if !pkgmgr.IsManagerSupported(pkg.InstalledBy, pkgmgr.GetCurrent()) {
 			continue
 		}
-
-		if status == ui.StatusSkipped {
-			tracker.SkipPackage(i, note)
-		} else {
-			tracker.CompletePackage(i, note)
+		if _, ok := cfg.PackageManagers[pkg.InstalledBy]; !ok {
+			return nil, fmt.Errorf("package manager %s not configured", pkg.InstalledBy)
 		}
-
-		successfulPackages = append(successfulPackages, pkg)
-	}
-
-	tracker.Stop()
-
-	// TODO bellow code should be handled by ui internal
-	cfg.Packages = config.MergePackages(cfg.Packages, successfulPackages)
-
-	if err := config.SaveToFile(cfg, cfg.ConfigDir); err != nil {
-		return fmt.Errorf("failed to save configuration: %w", err)
+		validPackages = append(validPackages, pkg.ToConfig())
 	}
 
-	return nil
+	return validPackages, nil
 }
 
-func processPackage(ctx context.Context, mgr pkgmgr.Manager, pkg config.PackageConfig) (ui.PackageStatus, string, error) {
-	installedPackages, err := mgr.List(ctx)
+// loadLockedPackages pins the desired packages of a manifest to the versions
+// recorded in its lockfile.
+func loadLockedPackages(filePath string, desired []config.PackageConfig) ([]config.PackageConfig, error) {
+	lockFile, err := formats.LoadLockFile(formats.LockFilePath(filePath))
 	if err != nil {
-		return ui.StatusFailed, "", fmt.Errorf("failed to list packages: %w", err)
-	}
-
-	var installedPkg *pkgmgr.Package
-	for i := range installedPackages {
-		if installedPackages[i].Name == pkg.Name {
-			installedPkg = &installedPackages[i]
-			break
-		}
-	}
-
-	if installedPkg != nil {
-		if version.Equal(installedPkg.Version, pkg.Version) {
-			return ui.StatusSkipped, "already installed", nil
-		}
-
-		if err := mgr.Uninstall(ctx, pkg.Name); err != nil {
-			return ui.StatusFailed, "", fmt.Errorf("failed to uninstall: %w", err)
-		}
-
-		packageWithVersion := pkg.Name
-		if pkg.Version != "" {
-			packageWithVersion = fmt.Sprintf("%s@%s", pkg.Name, pkg.Version)
-		}
-
-		if err := mgr.Install(ctx, packageWithVersion); err != nil {
-			return ui.StatusFailed, "", fmt.Errorf("failed to install: %w", err)
-		}
-
-		return ui.StatusSuccess, "reinstalled", nil
-	}
-
-	packageWithVersion := pkg.Name
-	if pkg.Version != "" {
-		packageWithVersion = fmt.Sprintf("%s@%s", pkg.Name, pkg.Version)
+		return nil, err
 	}
-
-	if err := mgr.Install(ctx, packageWithVersion); err != nil {
-		return ui.StatusFailed, "", fmt.Errorf("failed to install: %w", err)
-	}
-
-	return ui.StatusSuccess, "installed", nil
+	return lockFile.Resolve(runtime.GOOS, desired)
 }
 
-func getManager(managerType pkgmgr.ManagerType, mgrConfig config.PackageManagerConfig) (pkgmgr.Manager, error) {
+// getManager creates the configured manager of the given type.
+// With --dry-run, its mutating operations are recorded instead of executed.
+func getManager(cfg *config.Config, managerType pkgmgr.ManagerType) (pkgmgr.Manager, error) {
+	mgrConfig := cfg.PackageManagers[managerType]
 	if mgrConfig.ExecutablePath == "" {
 		return nil, fmt.Errorf("executable path of %s not configured", managerType)
 	}
 
-	mgr, err := newPackageManager(managerType, mgrConfig.ExecutablePath)
+	mgr, err := pkgmgr.NewManager(managerType, mgrConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create manager %s: %w", managerType, err)
 	}
-	return mgr, nil
-}
-
-func newPackageManager(managerType pkgmgr.ManagerType, executablePath string) (pkgmgr.Manager, error) {
-	if managerType == pkgmgr.ManagerTypeScoop {
-		return scoop.New(&scoop.Config{
-			ExecutablePath: executablePath,
-		}), nil
+	if cfg.DryRun {
+		return dryrun.NewManager(managerType, mgr, recorder), nil
 	}
-	return nil, nil
+	return mgr, nil
 }
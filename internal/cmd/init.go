@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"devctl/internal/config"
+	"devctl/internal/dryrun"
 	"devctl/internal/installer"
 	"devctl/internal/ui"
 	"devctl/pkg/executil"
@@ -28,6 +29,12 @@ func NewCmdInit(cfg *config.Config) *cobra.Command {
 }
 
 func runInit(cfg *config.Config) error {
+	unlock, err := lockConfig(cfg)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	out := ui.NewDefaultOutput()
 
 	currentPlatform := pkgmgr.GetCurrent()
@@ -56,12 +63,12 @@ func runInit(cfg *config.Config) error {
 	}
 
 	for _, mgr := range uninstalled {
-		if err := attemptAutoInstall(out, mgr.Type, string(currentPlatform), cfg.Debug); err != nil {
+		if err := attemptAutoInstall(out, cfg, mgr.Type, string(currentPlatform)); err != nil {
 			out.Error(fmt.Sprintf("Failed to install %s: %v", mgr.Type, err))
 			continue
 		}
 
-		path := executil.LookPath(string(mgr.Type))
+		path := lookPath(mgr.Type)
 		if path != "" {
 			detectResult[mgr.Type] = PackageManagerInfo{
 				Type:           mgr.Type,
@@ -86,7 +93,7 @@ func detectPackageManagers(p pkgmgr.Platform) map[pkgmgr.ManagerType]PackageMana
 	supportedManagers := pkgmgr.GetSupportedManagers(p)
 
 	for _, mgr := range supportedManagers {
-		path := executil.LookPath(string(mgr))
+		path := lookPath(mgr)
 
 		managers[mgr] = PackageManagerInfo{
 			Type:           mgr,
@@ -98,6 +105,15 @@ func detectPackageManagers(p pkgmgr.Platform) map[pkgmgr.ManagerType]PackageMana
 	return managers
 }
 
+// lookPath returns the executable path of a package manager, using the
+// backend's own lookup when it registered one.
+func lookPath(managerType pkgmgr.ManagerType) string {
+	if d, ok := pkgmgr.Lookup(managerType); ok && d.LookPath != nil {
+		return d.LookPath()
+	}
+	return executil.LookPath(string(managerType))
+}
+
 func displayDetectionResults(out ui.Output, results map[pkgmgr.ManagerType]PackageManagerInfo, p pkgmgr.Platform) {
 	managers := make([]ui.ManagerStatus, 0, len(results))
 	for _, mgr := range results {
@@ -136,21 +152,23 @@ func saveConfiguration(out ui.Output, cfg *config.Config, results map[pkgmgr.Man
 	}
 	cfg.PackageManagers = packageManagers
 
-	if err := config.SaveToFile(cfg, cfg.ConfigDir); err != nil {
+	if err := saveConfig(cfg); err != nil {
 		return fmt.Errorf("failed to save configuration: %w", err)
 	}
 
-	configPath := fmt.Sprintf("%s/%s.json", cfg.ConfigDir, config.AppName)
-	out.Println(fmt.Sprintf("Configuration saved to: %s", configPath))
+	out.Println(fmt.Sprintf("Configuration saved to: %s", config.FilePath(cfg.ConfigDir)))
 
 	return nil
 }
 
-func attemptAutoInstall(out ui.Output, managerType pkgmgr.ManagerType, platformStr string, debug bool) error {
+func attemptAutoInstall(out ui.Output, cfg *config.Config, managerType pkgmgr.ManagerType, platformStr string) error {
 	inst := installer.GetInstaller(managerType)
 	if inst == nil {
 		return fmt.Errorf("no installer available for %s", managerType)
 	}
+	if cfg.DryRun {
+		inst = dryrun.NewInstaller(inst, recorder)
+	}
 
 	canAuto, err := inst.CanAutoInstall()
 	if !canAuto {
@@ -173,7 +191,7 @@ func attemptAutoInstall(out ui.Output, managerType pkgmgr.ManagerType, platformS
 
 	cmd := inst.GetInstallCommand()
 	slog.Debug("installer command", slog.String("manager", string(managerType)), slog.String("cmd", cmd))
-	if debug {
+	if cfg.Debug {
 		out.PrintInstallCommand(cmd)
 	}
 
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"devctl/internal/config"
+	"devctl/internal/formats"
+	"devctl/internal/inventory"
+	"devctl/internal/plan"
+	"errors"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdLock(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lock <file>",
+		Short: "Pin the packages of a manifest to their installed versions",
+		Long: fmt.Sprintf(`Resolves every package of a manifest for the current platform against the installed packages and writes the exact versions to a lockfile next to the manifest, named after the manifest with the extension replaced by %s.
+
+The packages must be installed and satisfy the manifest, so run 'devctl apply' first. Sections of other platforms in an existing lockfile are kept.
+
+Use 'devctl import --frozen' to install exactly the locked versions.`, formats.LockFileSuffix),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runLock(cfg, args[0])
+		},
+	}
+	return cmd
+}
+
+func runLock(cfg *config.Config, filePath string) error {
+	ctx := context.Background()
+
+	desired, err := loadDesiredPackages(cfg, filePath)
+	if err != nil {
+		return err
+	}
+	if len(desired) == 0 {
+		fmt.Println("No valid packages to lock")
+		return nil
+	}
+
+	installed, err := listInstalled(ctx, cfg, usedManagerTypes(desired))
+	if err != nil {
+		return err
+	}
+
+	locked, err := resolveLocked(desired, installed)
+	if err != nil {
+		return err
+	}
+
+	lockPath := formats.LockFilePath(filePath)
+	lockFile, err := formats.LoadLockFile(lockPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		lockFile, err = &formats.LockFile{}, nil
+	}
+	if err != nil {
+		return err
+	}
+	lockFile.Lock(runtime.GOOS, locked, time.Now())
+
+	if cfg.DryRun {
+		recorder.RecordWrite(lockPath)
+		return nil
+	}
+
+	if err := formats.SaveLockFile(lockPath, lockFile); err != nil {
+		return err
+	}
+
+	fmt.Printf("Locked %d packages to: %s\n", len(locked), lockPath)
+	return nil
+}
+
+// resolveLocked resolves the desired packages to the installed packages.
+// It fails if a desired package is not installed or does not satisfy the manifest.
+func resolveLocked(desired []config.PackageConfig, installed inventory.Installed) ([]formats.LockedPackage, error) {
+	p := plan.Build(desired, installed, plan.Options{})
+
+	var problems []string
+	locked := make([]formats.LockedPackage, 0, len(p.Actions))
+	for i, a := range p.Actions {
+		if a.Type != plan.ActionNoop {
+			problems = append(problems, fmt.Sprintf("%s %s needs %s", a.InstalledBy, a.Name, a.Type))
+			continue
+		}
+		live := inventory.FindFrom(installed[a.InstalledBy], a.Repository, a.Name)
+		locked = append(locked, formats.LockedPackage{
+			Name:        a.Name,
+			Requested:   desired[i].Version,
+			Version:     live.Version,
+			InstalledBy: a.InstalledBy,
+			Repository:  live.Repository,
+		})
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("installed packages do not match the manifest, run 'devctl apply' first:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return locked, nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"devctl/internal/config"
+	"devctl/internal/formats"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdManifest(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "manifest",
+		Short: "Work with manifest files",
+	}
+
+	cmd.AddCommand(NewCmdManifestMigrate(cfg))
+
+	return cmd
+}
+
+func NewCmdManifestMigrate(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate <file>",
+		Short: "Upgrade a manifest to the current schema version",
+		Long:  `Rewrites a manifest written by an older version of devctl in place, using the current schema version.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runManifestMigrate(cfg, args[0])
+		},
+	}
+	return cmd
+}
+
+func runManifestMigrate(cfg *config.Config, filePath string) error {
+	f, version, err := formats.LoadToolManifestFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	if version == formats.CurrentSchemaVersion {
+		fmt.Printf("%s is already at schema version %d\n", filePath, version)
+		return nil
+	}
+
+	if cfg.DryRun {
+		recorder.RecordWrite(filePath)
+		return nil
+	}
+
+	if err := formats.SaveToolManifestFile(filePath, f); err != nil {
+		return err
+	}
+
+	fmt.Printf("Migrated %s from schema version %d to %d\n", filePath, version, formats.CurrentSchemaVersion)
+	return nil
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"devctl/internal/config"
+	"devctl/internal/ui"
+	"devctl/pkg/pkgmgr"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdOutdated(cfg *config.Config) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "outdated",
+		Short: "List tracked packages with newer versions available",
+		Long: `Asks every configured package manager which installed packages can be upgraded and lists the ones tracked in the configuration file.
+
+Package managers that cannot report outdated packages are skipped. Nothing is installed or upgraded.`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runOutdated(cfg, jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "print the report as JSON")
+
+	return cmd
+}
+
+// outdatedReport is the JSON form of the outdated command output.
+type outdatedReport struct {
+	Packages []outdatedPackage `json:"packages"`
+	// Unsupported are the package managers that cannot report outdated packages.
+	Unsupported []pkgmgr.ManagerType `json:"unsupported,omitempty"`
+}
+
+type outdatedPackage struct {
+	Name        string             `json:"name"`
+	InstalledBy pkgmgr.ManagerType `json:"installedBy"`
+	Repository  string             `json:"repository,omitempty"`
+	Installed   string             `json:"installed"`
+	// Tracked is the version constraint of the package in the configuration.
+	Tracked string `json:"tracked,omitempty"`
+	Latest  string `json:"latest"`
+	// Pinned packages are skipped by 'devctl upgrade' unless --force is given.
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+func runOutdated(cfg *config.Config, jsonOutput bool) error {
+	if len(cfg.PackageManagers) == 0 {
+		return fmt.Errorf("no package managers configured, run 'devctl init' first")
+	}
+
+	report, err := collectOutdated(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	out := ui.NewDefaultOutput()
+	for _, t := range report.Unsupported {
+		out.Warning(fmt.Sprintf("%s cannot report outdated packages, skipped", t))
+	}
+	// Packages tracked from several repositories are shown as repo/name.
+	count := map[string]int{}
+	for _, p := range report.Packages {
+		count[string(p.InstalledBy)+":"+p.Name]++
+	}
+	entries := make([]ui.OutdatedEntry, 0, len(report.Packages))
+	for _, p := range report.Packages {
+		name := p.Name
+		if p.Repository != "" && count[string(p.InstalledBy)+":"+p.Name] > 1 {
+			name = p.Repository + "/" + p.Name
+		}
+		entries = append(entries, ui.OutdatedEntry{
+			Name:      name,
+			Manager:   string(p.InstalledBy),
+			Installed: p.Installed,
+			Tracked:   p.Tracked,
+			Latest:    p.Latest,
+			Pinned:    p.Pinned,
+		})
+	}
+	out.PrintOutdated(entries)
+	return nil
+}
+
+// collectOutdated asks each package manager with tracked packages for its
+// outdated packages and keeps the tracked ones.
+func collectOutdated(ctx context.Context, cfg *config.Config) (*outdatedReport, error) {
+	report := &outdatedReport{Packages: []outdatedPackage{}}
+
+	for _, mgrType := range managerTypes(cfg) {
+		var tracked []config.PackageConfig
+		for _, pkg := range cfg.Packages {
+			if pkg.InstalledBy == mgrType {
+				tracked = append(tracked, pkg)
+			}
+		}
+		if len(tracked) == 0 {
+			continue
+		}
+
+		mgr, err := getManager(cfg, mgrType)
+		if err != nil {
+			return nil, err
+		}
+		lister, ok := mgr.(pkgmgr.OutdatedLister)
+		if !ok {
+			report.Unsupported = append(report.Unsupported, mgrType)
+			continue
+		}
+		pkgs, err := lister.Outdated(ctx)
+		if errors.Is(err, pkgmgr.ErrUnsupported) {
+			report.Unsupported = append(report.Unsupported, mgrType)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list outdated packages of %s: %w", mgrType, err)
+		}
+
+		for _, p := range pkgs {
+			for _, pkg := range tracked {
+				if !isOutdated(pkg, p) {
+					continue
+				}
+				report.Packages = append(report.Packages, outdatedPackage{
+					Name:        p.Name,
+					InstalledBy: mgrType,
+					Repository:  cmp.Or(p.Repository, pkg.Repository),
+					Installed:   p.Version,
+					Tracked:     pkg.Version,
+					Latest:      p.Latest,
+					Pinned:      pkg.Pinned,
+				})
+			}
+		}
+	}
+
+	slices.SortFunc(report.Packages, func(a, b outdatedPackage) int {
+		return cmp.Or(
+			cmp.Compare(a.InstalledBy, b.InstalledBy),
+			cmp.Compare(a.Repository, b.Repository),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	return report, nil
+}
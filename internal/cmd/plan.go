@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"devctl/internal/config"
+	"devctl/internal/plan"
+	"devctl/internal/ui"
+	"devctl/pkg/pkgmgr"
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdPlan(cfg *config.Config) *cobra.Command {
+	var outFile string
+	var prune bool
+
+	cmd := &cobra.Command{
+		Use:   "plan <file>",
+		Short: "Show the changes needed to apply a manifest",
+		Long: `Compares a manifest with the installed packages and prints the actions 'devctl apply' would take.
+
+Use -o to save the plan so it can be reviewed and then executed with 'devctl apply --plan'.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runPlan(cfg, args[0], outFile, prune)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outFile, "output", "o", "", "save the plan to a file")
+	cmd.Flags().BoolVar(&prune, "prune", false, "remove tracked packages that are not in the manifest")
+
+	return cmd
+}
+
+func runPlan(cfg *config.Config, filePath, outFile string, prune bool) error {
+	p, err := buildPlan(context.Background(), cfg, filePath, prune, false)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		fmt.Println("No valid packages to plan")
+		return nil
+	}
+
+	out := ui.NewDefaultOutput()
+	printPlan(out, p)
+
+	if outFile != "" && cfg.DryRun {
+		recorder.RecordWrite(outFile)
+	} else if outFile != "" {
+		if err := plan.Save(outFile, p); err != nil {
+			return err
+		}
+		out.Println(fmt.Sprintf("Plan saved to: %s", outFile))
+	}
+
+	return nil
+}
+
+// buildPlan loads a manifest and plans it against the installed packages.
+// With frozen, the packages are pinned to the versions of the manifest's lockfile.
+// Returns nil if the manifest has no package for the current platform.
+func buildPlan(ctx context.Context, cfg *config.Config, filePath string, prune, frozen bool) (*plan.Plan, error) {
+	desired, err := loadDesiredPackages(cfg, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if frozen && len(desired) > 0 {
+		if desired, err = loadLockedPackages(filePath, desired); err != nil {
+			return nil, err
+		}
+	}
+	if len(desired) == 0 {
+		return nil, nil
+	}
+
+	types := usedManagerTypes(desired)
+	if prune {
+		types = managerTypes(cfg)
+	}
+	installed, err := listInstalled(ctx, cfg, types)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan.Build(desired, installed, plan.Options{Prune: prune, Tracked: cfg.Packages}), nil
+}
+
+// usedManagerTypes returns the package managers used by pkgs in order of first use.
+func usedManagerTypes(pkgs []config.PackageConfig) []pkgmgr.ManagerType {
+	var types []pkgmgr.ManagerType
+	for _, pkg := range pkgs {
+		if !slices.Contains(types, pkg.InstalledBy) {
+			types = append(types, pkg.InstalledBy)
+		}
+	}
+	return types
+}
+
+func printPlan(out ui.Output, p *plan.Plan) {
+	actions := make([]ui.PlanAction, 0, len(p.Actions))
+	for _, a := range p.Actions {
+		actions = append(actions, ui.PlanAction{
+			Type:    string(a.Type),
+			Name:    a.Name,
+			Manager: string(a.InstalledBy),
+			From:    a.CurrentVersion,
+			To:      a.Version,
+		})
+	}
+	out.PrintPlan(actions)
+
+	if !p.HasChanges() {
+		out.Println("No changes. Installed packages match the manifest.")
+	}
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"devctl/internal/config"
+	"devctl/internal/journal"
+	"devctl/internal/plan"
+	"devctl/internal/ui"
+	"devctl/pkg/pkgmgr"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdRecover(cfg *config.Config) *cobra.Command {
+	var finish bool
+	var undo bool
+
+	cmd := &cobra.Command{
+		Use:   "recover",
+		Short: "Finish or undo an interrupted atomic import",
+		Long: `Reads the journal left behind by an 'import --atomic' that did not complete and either finishes the remaining actions or undoes the actions that were taken, last first.
+
+The installed packages are checked before each action, so recover can be run again if it is interrupted itself.`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runRecover(cfg, finish)
+		},
+	}
+
+	cmd.Flags().BoolVar(&finish, "finish", false, "execute the remaining actions of the interrupted run")
+	cmd.Flags().BoolVar(&undo, "undo", false, "undo the actions taken by the interrupted run")
+	cmd.MarkFlagsMutuallyExclusive("finish", "undo")
+	cmd.MarkFlagsOneRequired("finish", "undo")
+
+	return cmd
+}
+
+func runRecover(cfg *config.Config, finish bool) error {
+	unlock, err := lockConfig(cfg)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	j, err := journal.Load(journal.Path(cfg.DataDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Println("Nothing to recover")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	out := ui.NewDefaultOutput()
+	out.Info(fmt.Sprintf("Found an interrupted run from %s with %d action(s)", j.StartedAt.Local().Format("2006-01-02 15:04:05"), len(j.Plan.Actions)))
+
+	if finish {
+		if err := finishJournal(ctx, cfg, j); err != nil {
+			return err
+		}
+		out.Success("Finished the interrupted run")
+		return nil
+	}
+
+	if err := undoJournal(ctx, cfg, j); err != nil {
+		return err
+	}
+	out.Success("Undid the interrupted run")
+	return nil
+}
+
+// finishJournal executes the actions of the journal that did not take effect
+// yet, records the whole plan in the configuration and removes the journal.
+func finishJournal(ctx context.Context, cfg *config.Config, j *journal.Journal) error {
+	p := j.Plan
+	installed, err := listInstalled(ctx, cfg, p.ManagerTypes())
+	if err != nil {
+		return err
+	}
+
+	out := ui.NewDefaultOutput()
+	for i, a := range p.Actions {
+		if a.Type == plan.ActionNoop || a.Satisfied(installed[a.InstalledBy]) {
+			continue
+		}
+
+		mgr, err := getManager(cfg, a.InstalledBy)
+		if err != nil {
+			return err
+		}
+		if !cfg.DryRun {
+			if err := j.Start(i); err != nil {
+				return err
+			}
+		}
+		if err := a.Execute(ctx, mgr); err != nil {
+			return fmt.Errorf("%s %s: %w; run 'devctl recover --undo' to undo the run", a.InstalledBy, a.Name, err)
+		}
+		if !cfg.DryRun {
+			if err := j.Done(i); err != nil {
+				return err
+			}
+		}
+		out.Success(fmt.Sprintf("%s %s %s", actionNote(a.Type), a.InstalledBy, a.Name))
+	}
+
+	succeeded := make([]bool, len(p.Actions))
+	for i := range succeeded {
+		succeeded[i] = true
+	}
+	trackResults(cfg, p.Actions, succeeded)
+	if err := saveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	return removeJournal(cfg, j)
+}
+
+// undoJournal undoes the started actions of the journal, last started first,
+// and removes the journal once all of them are undone. The configuration is
+// left untouched, since it is only updated when a run completes.
+func undoJournal(ctx context.Context, cfg *config.Config, j *journal.Journal) error {
+	indexes := j.Undo()
+
+	var types []pkgmgr.ManagerType
+	for _, i := range indexes {
+		t := j.Plan.Actions[i].InstalledBy
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	installed, err := listInstalled(ctx, cfg, types)
+	if err != nil {
+		return fmt.Errorf("failed to roll back, run 'devctl recover --undo' to retry: %w", err)
+	}
+
+	out := ui.NewDefaultOutput()
+	if len(indexes) > 0 {
+		out.Warning(fmt.Sprintf("Rolling back %d action(s)", len(indexes)))
+	}
+
+	var errs []error
+	for _, i := range indexes {
+		a := j.Plan.Actions[i]
+		mgr, err := getManager(cfg, a.InstalledBy)
+		if err == nil {
+			err = a.Undo(ctx, mgr, installed[a.InstalledBy])
+		}
+		if err != nil {
+			out.Error(fmt.Sprintf("%s %s: %v", a.InstalledBy, a.Name, err))
+			errs = append(errs, fmt.Errorf("%s %s: %w", a.InstalledBy, a.Name, err))
+			continue
+		}
+		out.Info(fmt.Sprintf("Rolled back %s of %s %s", a.Type, a.InstalledBy, a.Name))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to roll back, run 'devctl recover --undo' to retry: %w", errors.Join(errs...))
+	}
+
+	return removeJournal(cfg, j)
+}
+
+func removeJournal(cfg *config.Config, j *journal.Journal) error {
+	if cfg.DryRun {
+		recorder.Record("remove " + journal.Path(cfg.DataDir))
+		return nil
+	}
+	return j.Remove()
+}
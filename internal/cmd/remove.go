@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"devctl/internal/config"
+	"devctl/internal/inventory"
+	"devctl/internal/plan"
+	"devctl/internal/ui"
+	"devctl/pkg/cmdutil"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdRemove(cfg *config.Config) *cobra.Command {
+	var keepInstalled bool
+
+	cmd := &cobra.Command{
+		Use:   "remove [<manager>:][<repository>/]<name>...",
+		Short: "Uninstall packages and stop tracking them",
+		Long: `Uninstalls the given tracked packages with their package manager and removes them from the configuration file. Packages that are tracked but no longer installed are only removed from the configuration file.
+
+Prefix the name with the package manager, e.g. 'brew:git', if the same name is tracked by more than one package manager, and with the repository, e.g. 'scoop:versions/python', if it is tracked from more than one repository. With --keep-installed, the packages are only removed from the configuration file.
+
+If any package fails to uninstall, the packages removed by this command are installed again and the configuration file is left untouched.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			refs := make([]config.PackageConfig, 0, len(args))
+			for _, arg := range args {
+				ref, err := parsePackageArg(arg, false)
+				if err != nil {
+					return cmdutil.FlagErrorWrap(err)
+				}
+				refs = append(refs, ref)
+			}
+			return runRemove(cfg, refs, keepInstalled)
+		},
+	}
+
+	cmd.Flags().BoolVar(&keepInstalled, "keep-installed", false, "only stop tracking the packages, leave them installed")
+
+	return cmd
+}
+
+func runRemove(cfg *config.Config, refs []config.PackageConfig, keepInstalled bool) error {
+	unlock, err := lockConfig(cfg)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	removed, err := findTracked(cfg.Packages, refs)
+	if err != nil {
+		return err
+	}
+
+	if keepInstalled {
+		cfg.Packages = config.RemovePackages(cfg.Packages, removed)
+		if err := saveConfig(cfg); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+		out := ui.NewDefaultOutput()
+		for _, pkg := range removed {
+			out.Success(fmt.Sprintf("Stopped tracking %s %s", pkg.InstalledBy, pkg.Name))
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	installed, err := listInstalled(ctx, cfg, usedManagerTypes(removed))
+	if err != nil {
+		return err
+	}
+
+	p := &plan.Plan{Platform: runtime.GOOS}
+	var missing []config.PackageConfig
+	for _, pkg := range removed {
+		live := inventory.FindFrom(installed[pkg.InstalledBy], pkg.Repository, pkg.Name)
+		if live == nil {
+			missing = append(missing, pkg)
+			continue
+		}
+		p.Actions = append(p.Actions, plan.Action{
+			Type:           plan.ActionRemove,
+			Name:           pkg.Name,
+			InstalledBy:    pkg.InstalledBy,
+			Repository:     pkg.Repository,
+			CurrentVersion: live.Version,
+		})
+	}
+
+	// Packages that are not installed are dropped along with the removals,
+	// since applyPlan only saves the configuration if all of them succeed.
+	cfg.Packages = config.RemovePackages(cfg.Packages, missing)
+	if len(p.Actions) == 0 {
+		if err := saveConfig(cfg); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+		fmt.Println("Nothing to uninstall, stopped tracking the packages")
+		return nil
+	}
+
+	return applyPlan(ctx, cfg, p, defaultJobs, true)
+}
+
+// findTracked returns the tracked packages that refs refer to. A ref names a
+// package by name or by repository and name, and must match exactly one
+// tracked package.
+func findTracked(tracked []config.PackageConfig, refs []config.PackageConfig) ([]config.PackageConfig, error) {
+	var found []config.PackageConfig
+	for _, ref := range refs {
+		var matches []config.PackageConfig
+		for _, pkg := range tracked {
+			if refersTo(ref, pkg) {
+				matches = append(matches, pkg)
+			}
+		}
+		switch {
+		case len(matches) == 0 && ref.InstalledBy != "":
+			return nil, fmt.Errorf("package %s:%s is not tracked", ref.InstalledBy, ref.Name)
+		case len(matches) == 0:
+			return nil, fmt.Errorf("package %s is not tracked", ref.Name)
+		case len(matches) > 1 && ref.InstalledBy == "" && matches[0].InstalledBy != matches[1].InstalledBy:
+			return nil, fmt.Errorf("package %s is tracked by several package managers, use <manager>:%s", ref.Name, ref.Name)
+		case len(matches) > 1:
+			return nil, fmt.Errorf("package %s is tracked from several repositories, use <manager>:<repository>/%s", ref.Name, ref.Name)
+		}
+		found = append(found, matches[0])
+	}
+	return found, nil
+}
+
+// refersTo reports whether ref, a package given on the command line, names
+// the tracked package pkg. The name of ref may include the repository as
+// <repository>/<name>; without a package manager, ref matches any manager.
+func refersTo(ref, pkg config.PackageConfig) bool {
+	named := pkg.Name == ref.Name || (pkg.Repository != "" && pkg.Repository+"/"+pkg.Name == ref.Name)
+	return named && (ref.InstalledBy == "" || pkg.InstalledBy == ref.InstalledBy)
+}
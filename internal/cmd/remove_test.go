@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"devctl/internal/config"
+	"devctl/pkg/pkgmgr"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindTracked(t *testing.T) {
+	brew, scoop := pkgmgr.ManagerTypeBrew, pkgmgr.ManagerTypeScoop
+	tracked := []config.PackageConfig{
+		{Name: "git", InstalledBy: brew},
+		{Name: "jq", InstalledBy: brew},
+		{Name: "git", InstalledBy: scoop, Repository: "main"},
+		{Name: "python", InstalledBy: scoop, Repository: "main"},
+		{Name: "python", InstalledBy: scoop, Repository: "versions"},
+	}
+
+	tests := []struct {
+		name    string
+		ref     config.PackageConfig
+		want    config.PackageConfig
+		wantErr string
+	}{
+		{name: "name", ref: config.PackageConfig{Name: "jq"}, want: tracked[1]},
+		{name: "manager and name", ref: config.PackageConfig{Name: "git", InstalledBy: scoop}, want: tracked[2]},
+		{name: "repository and name", ref: config.PackageConfig{Name: "versions/python"}, want: tracked[4]},
+		{name: "several managers", ref: config.PackageConfig{Name: "git"}, wantErr: "tracked by several package managers, use <manager>:git"},
+		{name: "several repositories", ref: config.PackageConfig{Name: "python", InstalledBy: scoop}, wantErr: "tracked from several repositories"},
+		{name: "untracked", ref: config.PackageConfig{Name: "curl"}, wantErr: "package curl is not tracked"},
+		{name: "untracked by manager", ref: config.PackageConfig{Name: "jq", InstalledBy: scoop}, wantErr: "package scoop:jq is not tracked"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findTracked(tracked, []config.PackageConfig{tt.ref})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []config.PackageConfig{tt.want}, got)
+		})
+	}
+}
+
+func TestRefersTo(t *testing.T) {
+	pkg := config.PackageConfig{Name: "python", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "versions"}
+
+	tests := []struct {
+		ref  config.PackageConfig
+		want bool
+	}{
+		{ref: config.PackageConfig{Name: "python"}, want: true},
+		{ref: config.PackageConfig{Name: "versions/python"}, want: true},
+		{ref: config.PackageConfig{Name: "python", InstalledBy: pkgmgr.ManagerTypeScoop}, want: true},
+		{ref: config.PackageConfig{Name: "main/python"}, want: false},
+		{ref: config.PackageConfig{Name: "python", InstalledBy: pkgmgr.ManagerTypeBrew}, want: false},
+		{ref: config.PackageConfig{Name: "python3"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.ref.InstalledBy)+":"+tt.ref.Name, func(t *testing.T) {
+			require.Equal(t, tt.want, refersTo(tt.ref, pkg))
+		})
+	}
+}
@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"devctl/internal/config"
+	"devctl/internal/dryrun"
 	"devctl/internal/logging"
 	"devctl/pkg/cmdutil"
+	"devctl/pkg/pkgmgr"
+	"devctl/pkg/pkgmgr/plugin"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -12,10 +15,19 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+
+	// Built-in package manager backends register themselves with pkgmgr.
+	_ "devctl/pkg/pkgmgr/apt"
+	_ "devctl/pkg/pkgmgr/brew"
+	_ "devctl/pkg/pkgmgr/pwsh"
+	_ "devctl/pkg/pkgmgr/scoop"
 )
 
 var cfg = config.Init()
 
+// recorder collects the operations skipped because of --dry-run.
+var recorder = dryrun.NewRecorder()
+
 func NewCmdRoot() (*cobra.Command, error) {
 	cmd := &cobra.Command{
 		Use:          "devctl",
@@ -27,12 +39,28 @@ func NewCmdRoot() (*cobra.Command, error) {
 	cfg.AddFlags(cmd.PersistentFlags())
 
 	setupLogging(cfg)
+	registerPlugins(cfg)
 
 	cmd.SetFlagErrorFunc(rootFlagErrorFunc)
 
+	cobra.OnFinalize(func() {
+		recorder.Print(os.Stdout)
+	})
+
 	cmd.AddCommand(NewCmdInit(cfg))
 	cmd.AddCommand(NewCmdImport(cfg))
 	cmd.AddCommand(NewCmdExport(cfg))
+	cmd.AddCommand(NewCmdAdd(cfg))
+	cmd.AddCommand(NewCmdRemove(cfg))
+	cmd.AddCommand(NewCmdStatus(cfg))
+	cmd.AddCommand(NewCmdAdopt(cfg))
+	cmd.AddCommand(NewCmdOutdated(cfg))
+	cmd.AddCommand(NewCmdUpgrade(cfg))
+	cmd.AddCommand(NewCmdPlan(cfg))
+	cmd.AddCommand(NewCmdApply(cfg))
+	cmd.AddCommand(NewCmdRecover(cfg))
+	cmd.AddCommand(NewCmdManifest(cfg))
+	cmd.AddCommand(NewCmdLock(cfg))
 
 	return cmd, nil
 }
@@ -62,6 +90,42 @@ func setupLogging(cfg *config.Config) {
 	slog.SetDefault(logger)
 }
 
+// registerPlugins registers the external package manager plugins found in
+// the plugins directory and on PATH. Plugins never replace built-in backends.
+func registerPlugins(cfg *config.Config) {
+	pluginDir := filepath.Join(cfg.DataDir, "plugins")
+	for _, p := range plugin.Discover(pluginDir) {
+		if _, ok := pkgmgr.Lookup(p.Type); ok {
+			slog.Debug("plugin shadowed by registered manager", slog.String("type", string(p.Type)), slog.String("path", p.Path))
+			continue
+		}
+		if err := pkgmgr.DefaultRegistry.Register(p.Descriptor()); err != nil {
+			slog.Warn("failed to register plugin", slog.String("path", p.Path), slog.Any("error", err))
+			continue
+		}
+		slog.Debug("registered plugin", slog.String("type", string(p.Type)), slog.String("path", p.Path))
+	}
+}
+
+// saveConfig writes the configuration file, or records the write with --dry-run.
+func saveConfig(cfg *config.Config) error {
+	if cfg.DryRun {
+		recorder.RecordWrite(config.FilePath(cfg.ConfigDir))
+		return nil
+	}
+	return config.SaveToFile(cfg, cfg.ConfigDir)
+}
+
+// lockConfig takes the lock of the configuration file for a command that
+// modifies it and reloads the file. With --dry-run nothing is written, so
+// no lock is taken.
+func lockConfig(cfg *config.Config) (func(), error) {
+	if cfg.DryRun {
+		return func() {}, nil
+	}
+	return config.Lock(cfg)
+}
+
 type CommandError struct {
 	error
 	ExitCode int
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"devctl/internal/config"
+	"devctl/internal/inventory"
+	"devctl/internal/ui"
+	"devctl/pkg/pkgmgr"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdStatus(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Compare tracked packages with installed packages",
+		Long: `Lists the installed packages of every configured package manager and compares them with the packages in the configuration file.
+
+Nothing is installed or removed. Exits with a non-zero status when a tracked package is missing or installed with a different version.`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runStatus(cfg)
+		},
+	}
+	return cmd
+}
+
+func runStatus(cfg *config.Config) error {
+	if len(cfg.PackageManagers) == 0 {
+		return fmt.Errorf("no package managers configured, run 'devctl init' first")
+	}
+
+	ctx := context.Background()
+	installed, err := listInstalled(ctx, cfg, managerTypes(cfg))
+	if err != nil {
+		return err
+	}
+
+	report := inventory.Compare(cfg.Packages, installed)
+	qualify := ambiguousNames(report.InSync, report.Mismatched, report.Missing, report.Untracked)
+
+	out := ui.NewDefaultOutput()
+	out.PrintStatusReport(ui.StatusReport{
+		InSync:     toStatusEntries(report.InSync, qualify),
+		Mismatched: toStatusEntries(report.Mismatched, qualify),
+		Missing:    toStatusEntries(report.Missing, qualify),
+		Untracked:  toStatusEntries(report.Untracked, qualify),
+	})
+
+	if report.HasDrift() {
+		return &CommandError{
+			error:    fmt.Errorf("%d package(s) out of sync", len(report.Mismatched)+len(report.Missing)),
+			ExitCode: 1,
+		}
+	}
+	return nil
+}
+
+// listInstalled calls List on each of the given package managers.
+func listInstalled(ctx context.Context, cfg *config.Config, types []pkgmgr.ManagerType) (inventory.Installed, error) {
+	installed := inventory.Installed{}
+	for _, mgrType := range types {
+		mgr, err := getManager(cfg, mgrType)
+		if err != nil {
+			return nil, err
+		}
+		pkgs, err := mgr.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list packages of %s: %w", mgrType, err)
+		}
+		installed[mgrType] = pkgs
+	}
+	return installed, nil
+}
+
+// ambiguousNames returns the manager and name of the packages that appear
+// more than once across groups, which only differ in their repository.
+func ambiguousNames(groups ...[]inventory.Entry) map[string]bool {
+	seen := map[string]int{}
+	for _, entries := range groups {
+		for _, e := range entries {
+			seen[string(e.InstalledBy)+":"+e.Name]++
+		}
+	}
+	ambiguous := map[string]bool{}
+	for k, n := range seen {
+		if n > 1 {
+			ambiguous[k] = true
+		}
+	}
+	return ambiguous
+}
+
+// toStatusEntries converts entries for display. Names in qualify are shown
+// with their repository, e.g. "versions/python".
+func toStatusEntries(entries []inventory.Entry, qualify map[string]bool) []ui.StatusEntry {
+	result := make([]ui.StatusEntry, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name
+		if e.Repository != "" && qualify[string(e.InstalledBy)+":"+e.Name] {
+			name = e.Repository + "/" + e.Name
+		}
+		result = append(result, ui.StatusEntry{
+			Name:      name,
+			Manager:   string(e.InstalledBy),
+			Tracked:   e.Tracked,
+			Installed: e.Installed,
+		})
+	}
+	return result
+}
+
+// managerTypes returns the configured package manager types in sorted order.
+func managerTypes(cfg *config.Config) []pkgmgr.ManagerType {
+	return slices.Sorted(maps.Keys(cfg.PackageManagers))
+}
@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"context"
+	"devctl/internal/config"
+	"devctl/internal/inventory"
+	"devctl/internal/ui"
+	"devctl/pkg/cmdutil"
+	"devctl/pkg/pkgmgr"
+	"devctl/pkg/version"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdUpgrade(cfg *config.Config) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "upgrade [[<manager>:][<repository>/]<package>...]",
+		Short: "Upgrade tracked packages to their latest version",
+		Long: `Upgrades the given tracked packages, or all tracked packages, with the native update command of their package manager and records the new versions in the configuration file.
+
+Prefix a package with the repository, e.g. 'scoop:versions/python', if the same name is tracked from more than one repository. Packages marked as pinned in the configuration file are skipped unless --force is given. Package managers that report outdated packages only upgrade the packages that are behind.`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runUpgrade(cfg, args, force)
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "upgrade pinned packages too")
+
+	return cmd
+}
+
+func runUpgrade(cfg *config.Config, names []string, force bool) error {
+	unlock, err := lockConfig(cfg)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if len(cfg.PackageManagers) == 0 {
+		return fmt.Errorf("no package managers configured, run 'devctl init' first")
+	}
+
+	selected, err := selectPackages(cfg.Packages, names)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		fmt.Println("No tracked packages to upgrade")
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	packageInfos := make([]ui.PackageInfo, len(selected))
+	for i, pkg := range selected {
+		packageInfos[i] = ui.PackageInfo{Name: pkg.Name, Version: pkg.Version}
+	}
+
+	tracker := ui.NewProgressTracker(packageInfos)
+	tracker.OnInterrupt(cancel)
+	tracker.Start()
+
+	indexes := map[pkgmgr.ManagerType][]int{}
+	for i, pkg := range selected {
+		if pkg.Pinned && !force {
+			tracker.SkipPackage(i, "pinned")
+			continue
+		}
+		indexes[pkg.InstalledBy] = append(indexes[pkg.InstalledBy], i)
+	}
+
+	var upgraded []config.PackageConfig
+	for _, mgrType := range managerTypes(cfg) {
+		if len(indexes[mgrType]) == 0 {
+			continue
+		}
+		upgraded = append(upgraded, upgradePackages(ctx, cfg, mgrType, selected, indexes[mgrType], tracker)...)
+	}
+
+	tracker.Stop()
+
+	cfg.Packages = config.MergePackages(cfg.Packages, upgraded)
+	if err := saveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("interrupted: %w", err)
+	}
+	return nil
+}
+
+// selectPackages returns the tracked packages that names refer to, or all
+// tracked packages if names is empty. A name is given as
+// [<manager>:][<repository>/]<name> and selects every tracked package it
+// refers to, but must include the repository if the package manager tracks
+// the name from several repositories.
+func selectPackages(tracked []config.PackageConfig, names []string) ([]config.PackageConfig, error) {
+	if len(names) == 0 {
+		return tracked, nil
+	}
+
+	var selected []config.PackageConfig
+	for _, name := range names {
+		ref, err := parsePackageArg(name, false)
+		if err != nil {
+			return nil, cmdutil.FlagErrorWrap(err)
+		}
+		var matches []config.PackageConfig
+		for _, pkg := range tracked {
+			if !refersTo(ref, pkg) {
+				continue
+			}
+			if slices.ContainsFunc(matches, func(m config.PackageConfig) bool { return m.InstalledBy == pkg.InstalledBy }) {
+				return nil, fmt.Errorf("package %s is tracked from several repositories, use <manager>:<repository>/%s", ref.Name, ref.Name)
+			}
+			matches = append(matches, pkg)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("package %s is not tracked", name)
+		}
+		selected = append(selected, matches...)
+	}
+	return selected, nil
+}
+
+// upgradePackages upgrades the packages at indexes of pkgs, which all belong
+// to mgrType, and returns them with their new versions.
+func upgradePackages(ctx context.Context, cfg *config.Config, mgrType pkgmgr.ManagerType, pkgs []config.PackageConfig, indexes []int, tracker *ui.ProgressTracker) []config.PackageConfig {
+	if ctx.Err() != nil {
+		for _, i := range indexes {
+			tracker.SkipPackage(i, "cancelled")
+		}
+		return nil
+	}
+	for _, i := range indexes {
+		tracker.StartPackage(i)
+	}
+
+	failAll := func(err error) []config.PackageConfig {
+		for _, i := range indexes {
+			tracker.FailPackage(i, err)
+		}
+		return nil
+	}
+
+	mgr, err := getManager(cfg, mgrType)
+	if err != nil {
+		return failAll(err)
+	}
+	upgrader, ok := mgr.(pkgmgr.Upgrader)
+	if !ok {
+		return failAll(fmt.Errorf("%s cannot upgrade packages in place", mgrType))
+	}
+
+	before, err := mgr.List(ctx)
+	if err != nil {
+		return failAll(fmt.Errorf("failed to list packages of %s: %w", mgrType, err))
+	}
+
+	var outdated []pkgmgr.OutdatedPackage
+	filter := false
+	if lister, ok := mgr.(pkgmgr.OutdatedLister); ok {
+		list, err := lister.Outdated(ctx)
+		switch {
+		case errors.Is(err, pkgmgr.ErrUnsupported):
+		case err != nil:
+			return failAll(fmt.Errorf("failed to list outdated packages of %s: %w", mgrType, err))
+		default:
+			filter, outdated = true, list
+		}
+	}
+
+	var pending []int
+	var names []string
+	for _, i := range indexes {
+		switch {
+		case inventory.FindFrom(before, pkgs[i].Repository, pkgs[i].Name) == nil:
+			tracker.FailPackage(i, pkgmgr.ErrNotInstalled)
+		case filter && !slices.ContainsFunc(outdated, func(p pkgmgr.OutdatedPackage) bool { return isOutdated(pkgs[i], p) }):
+			tracker.SkipPackage(i, "up to date")
+		default:
+			pending = append(pending, i)
+			names = append(names, pkgs[i].Name)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	upgradeErr := upgrader.Upgrade(ctx, names...)
+
+	// List again to record the new versions and, if the upgrade failed part
+	// way through, to find out which packages were upgraded anyway.
+	after, listErr := mgr.List(context.WithoutCancel(ctx))
+
+	var upgraded []config.PackageConfig
+	for _, i := range pending {
+		pkg := pkgs[i]
+		old := inventory.FindFrom(before, pkg.Repository, pkg.Name)
+		var live *pkgmgr.Package
+		if listErr == nil {
+			live = inventory.FindFrom(after, pkg.Repository, pkg.Name)
+		}
+		changed := live != nil && live.Version != old.Version
+
+		switch {
+		case upgradeErr != nil && !changed:
+			tracker.FailPackage(i, upgradeErr)
+			continue
+		case listErr != nil:
+			tracker.CompletePackage(i, "upgraded")
+			continue
+		case !changed:
+			tracker.CompletePackage(i, "upgraded")
+		default:
+			tracker.CompletePackage(i, fmt.Sprintf("upgraded to %s", live.Version))
+		}
+		if live != nil {
+			pkg.Version = upgradedVersion(pkg.Version, live.Version)
+			upgraded = append(upgraded, pkg)
+		}
+	}
+	return upgraded
+}
+
+// isOutdated reports whether the outdated package p, reported by the package
+// manager of pkg, is the tracked package pkg.
+func isOutdated(pkg config.PackageConfig, p pkgmgr.OutdatedPackage) bool {
+	return config.SamePackage(pkg, config.PackageConfig{Name: p.Name, InstalledBy: pkg.InstalledBy, Repository: p.Repository})
+}
+
+// upgradedVersion returns the version to track after upgrading a package to
+// installed. Ranges that still match are kept, exact versions and ranges the
+// new version falls outside of are replaced with the installed version.
+func upgradedVersion(tracked, installed string) string {
+	c, err := version.ParseConstraint(tracked)
+	if err != nil {
+		return installed
+	}
+	if _, exact := c.Exact(); !exact && c.Check(installed) {
+		return tracked
+	}
+	return installed
+}
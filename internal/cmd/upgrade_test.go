@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"devctl/internal/config"
+	"devctl/pkg/cmdutil"
+	"devctl/pkg/pkgmgr"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectPackages(t *testing.T) {
+	brew, scoop := pkgmgr.ManagerTypeBrew, pkgmgr.ManagerTypeScoop
+	tracked := []config.PackageConfig{
+		{Name: "git", InstalledBy: brew},
+		{Name: "git", InstalledBy: scoop, Repository: "main"},
+		{Name: "python", InstalledBy: scoop, Repository: "main"},
+		{Name: "python", InstalledBy: scoop, Repository: "versions"},
+	}
+
+	tests := []struct {
+		name    string
+		names   []string
+		want    []config.PackageConfig
+		wantErr string
+	}{
+		{name: "all packages", names: nil, want: tracked},
+		{name: "name of several managers", names: []string{"git"}, want: tracked[:2]},
+		{name: "manager and name", names: []string{"brew:git"}, want: tracked[:1]},
+		{name: "repository and name", names: []string{"scoop:versions/python"}, want: tracked[3:]},
+		{name: "several repositories", names: []string{"python"}, wantErr: "tracked from several repositories"},
+		{name: "untracked", names: []string{"apt:git"}, wantErr: "package apt:git is not tracked"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectPackages(tracked, tt.names)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSelectPackagesInvalidName(t *testing.T) {
+	_, err := selectPackages(nil, []string{":git"})
+
+	var flagErr *cmdutil.FlagError
+	require.ErrorAs(t, err, &flagErr)
+}
+
+func TestUpgradedVersion(t *testing.T) {
+	tests := []struct {
+		tracked   string
+		installed string
+		want      string
+	}{
+		{tracked: "", installed: "1.7.1", want: ""},
+		{tracked: "latest", installed: "1.7.1", want: "latest"},
+		{tracked: "1.6", installed: "1.7.1", want: "1.7.1"},
+		{tracked: "^1.6", installed: "1.7.1", want: "^1.6"},
+		{tracked: "^1.6", installed: "2.0.0", want: "2.0.0"},
+		{tracked: "not a version", installed: "1.7.1", want: "1.7.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tracked+" to "+tt.installed, func(t *testing.T) {
+			require.Equal(t, tt.want, upgradedVersion(tt.tracked, tt.installed))
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package config
 
 import (
+	"cmp"
 	"devctl/pkg/home"
 	"devctl/pkg/pkgmgr"
 	"fmt"
 	"path/filepath"
+	"slices"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/spf13/pflag"
@@ -16,27 +18,33 @@ const (
 
 // Config holds the configuration for devctl.
 type Config struct {
-	Debug     bool   `json:"-" env:"DEVCTL_DEBUG"`
-	ConfigDir string `json:"-" env:"DEVCTL_CONFIG_DIR"`
+	Debug     bool   `json:"-" yaml:"-" toml:"-" env:"DEVCTL_DEBUG"`
+	DryRun    bool   `json:"-" yaml:"-" toml:"-" env:"DEVCTL_DRY_RUN"`
+	ConfigDir string `json:"-" yaml:"-" toml:"-" env:"DEVCTL_CONFIG_DIR"`
 
-	DataDir         string                                      `json:"dataDir,omitempty"`
-	PackageManagers map[pkgmgr.ManagerType]PackageManagerConfig `json:"packageManagers,omitempty"`
-	Packages        []PackageConfig                             `json:"packages,omitempty"`
+	DataDir         string                                      `json:"dataDir,omitempty" yaml:"dataDir,omitempty" toml:"dataDir,omitempty"`
+	PackageManagers map[pkgmgr.ManagerType]PackageManagerConfig `json:"packageManagers,omitempty" yaml:"packageManagers,omitempty" toml:"packageManagers,omitempty"`
+	Packages        []PackageConfig                             `json:"packages,omitempty" yaml:"packages,omitempty" toml:"packages,omitempty"`
 }
 
 type PackageConfig struct {
-	Name        string             `json:"name,omitempty"`
-	Version     string             `json:"version,omitempty"`
-	InstalledBy pkgmgr.ManagerType `json:"installedBy,omitempty"`
+	Name        string             `json:"name,omitempty" yaml:"name,omitempty" toml:"name,omitempty"`
+	Version     string             `json:"version,omitempty" yaml:"version,omitempty" toml:"version,omitempty"`
+	InstalledBy pkgmgr.ManagerType `json:"installedBy,omitempty" yaml:"installedBy,omitempty" toml:"installedBy,omitempty"`
+	// Repository is the bucket, tap or repository the package comes from.
+	// Empty matches the package from any repository.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty" toml:"repository,omitempty"`
+	// Pinned excludes the package from 'devctl upgrade' unless --force is given.
+	Pinned bool `json:"pinned,omitempty" yaml:"pinned,omitempty" toml:"pinned,omitempty"`
 }
 
-type PackageManagerConfig struct {
-	Version        string `json:"version,omitempty"`
-	ExecutablePath string `json:"executablePath,omitempty"`
-}
+// PackageManagerConfig holds the configuration of a package manager.
+// It is passed as is to the backend registered for the manager type.
+type PackageManagerConfig = pkgmgr.ManagerConfig
 
 func (cfg *Config) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&cfg.Debug, "debug", cfg.Debug, "enable verbose output")
+	fs.BoolVar(&cfg.DryRun, "dry-run", cfg.DryRun, "print the commands and writes that would run without executing them")
 }
 
 func Init() *Config {
@@ -55,25 +63,62 @@ func Init() *Config {
 	return cfg
 }
 
-func MergePackages(existing, newPkgs []PackageConfig) []PackageConfig {
-	pkgMap := make(map[string]PackageConfig)
+// SamePackage reports whether a and b refer to the same package, that is
+// the same package manager, repository and name. An empty repository matches
+// any repository.
+func SamePackage(a, b PackageConfig) bool {
+	return a.InstalledBy == b.InstalledBy && a.Name == b.Name &&
+		(a.Repository == "" || b.Repository == "" || a.Repository == b.Repository)
+}
 
-	for _, pkg := range existing {
-		pkgMap[pkg.Name] = pkg
-	}
+// ComparePackages orders packages by package manager, repository and name.
+func ComparePackages(a, b PackageConfig) int {
+	return cmp.Or(
+		cmp.Compare(a.InstalledBy, b.InstalledBy),
+		cmp.Compare(a.Repository, b.Repository),
+		cmp.Compare(a.Name, b.Name),
+	)
+}
 
-	for _, pkg := range newPkgs {
-		pkgMap[pkg.Name] = pkg
-	}
+// MergePackages returns existing with the packages in newPkgs added or
+// replaced, sorted with ComparePackages. A package without a repository keeps
+// the repository of the package it replaces. Pinned is only ever set by the
+// user, so it is kept from the existing package.
+func MergePackages(existing, newPkgs []PackageConfig) []PackageConfig {
+	result := slices.Clone(existing)
 
-	result := make([]PackageConfig, 0, len(pkgMap))
-	for _, pkg := range pkgMap {
-		result = append(result, pkg)
+	for _, pkg := range newPkgs {
+		i := slices.IndexFunc(result, func(old PackageConfig) bool {
+			return SamePackage(old, pkg)
+		})
+		if i < 0 {
+			result = append(result, pkg)
+			continue
+		}
+		old := result[i]
+		if pkg.Repository == "" {
+			pkg.Repository = old.Repository
+		}
+		if old.Pinned {
+			pkg.Pinned = true
+		}
+		result[i] = pkg
 	}
 
+	slices.SortStableFunc(result, ComparePackages)
 	return result
 }
 
+// RemovePackages returns existing without the packages in removed.
+// Packages are matched with SamePackage.
+func RemovePackages(existing, removed []PackageConfig) []PackageConfig {
+	return slices.DeleteFunc(slices.Clone(existing), func(pkg PackageConfig) bool {
+		return slices.ContainsFunc(removed, func(r PackageConfig) bool {
+			return SamePackage(pkg, r)
+		})
+	})
+}
+
 func loadDefaults() *Config {
 	return &Config{
 		Debug:     false,
@@ -108,6 +153,9 @@ func (cfg *Config) merge(other *Config) {
 	if other.Debug {
 		cfg.Debug = other.Debug
 	}
+	if other.DryRun {
+		cfg.DryRun = other.DryRun
+	}
 	if other.DataDir != "" {
 		cfg.DataDir = other.DataDir
 	}
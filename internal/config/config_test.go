@@ -0,0 +1,115 @@
+package config
+
+import (
+	"testing"
+
+	"devctl/pkg/pkgmgr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePackages(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []PackageConfig
+		newPkgs  []PackageConfig
+		want     []PackageConfig
+	}{
+		{
+			name: "same name from different managers",
+			existing: []PackageConfig{
+				{Name: "node", Version: "20.11.1", InstalledBy: pkgmgr.ManagerTypeScoop},
+			},
+			newPkgs: []PackageConfig{
+				{Name: "node", Version: "20.11.0", InstalledBy: "nvm"},
+			},
+			want: []PackageConfig{
+				{Name: "node", Version: "20.11.0", InstalledBy: "nvm"},
+				{Name: "node", Version: "20.11.1", InstalledBy: pkgmgr.ManagerTypeScoop},
+			},
+		},
+		{
+			name: "same name from different repositories",
+			existing: []PackageConfig{
+				{Name: "python", Version: "3.12.2", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "main"},
+			},
+			newPkgs: []PackageConfig{
+				{Name: "python", Version: "3.11.8", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "versions"},
+			},
+			want: []PackageConfig{
+				{Name: "python", Version: "3.12.2", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "main"},
+				{Name: "python", Version: "3.11.8", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "versions"},
+			},
+		},
+		{
+			name: "replaced package keeps repository and pin",
+			existing: []PackageConfig{
+				{Name: "git", Version: "2.43.0", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "main", Pinned: true},
+			},
+			newPkgs: []PackageConfig{
+				{Name: "git", Version: "2.44.0", InstalledBy: pkgmgr.ManagerTypeScoop},
+			},
+			want: []PackageConfig{
+				{Name: "git", Version: "2.44.0", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "main", Pinned: true},
+			},
+		},
+		{
+			name: "repository is filled in",
+			existing: []PackageConfig{
+				{Name: "jq", InstalledBy: pkgmgr.ManagerTypeBrew},
+			},
+			newPkgs: []PackageConfig{
+				{Name: "jq", Version: "1.7.1", InstalledBy: pkgmgr.ManagerTypeBrew, Repository: "homebrew/core"},
+			},
+			want: []PackageConfig{
+				{Name: "jq", Version: "1.7.1", InstalledBy: pkgmgr.ManagerTypeBrew, Repository: "homebrew/core"},
+			},
+		},
+		{
+			name: "sorted by manager, repository and name",
+			existing: []PackageConfig{
+				{Name: "wget", InstalledBy: pkgmgr.ManagerTypeBrew},
+				{Name: "7zip", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "main"},
+			},
+			newPkgs: []PackageConfig{
+				{Name: "curl", InstalledBy: pkgmgr.ManagerTypeApt},
+				{Name: "firefox", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "extras"},
+				{Name: "jq", InstalledBy: pkgmgr.ManagerTypeBrew},
+			},
+			want: []PackageConfig{
+				{Name: "curl", InstalledBy: pkgmgr.ManagerTypeApt},
+				{Name: "jq", InstalledBy: pkgmgr.ManagerTypeBrew},
+				{Name: "wget", InstalledBy: pkgmgr.ManagerTypeBrew},
+				{Name: "firefox", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "extras"},
+				{Name: "7zip", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "main"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergePackages(tt.existing, tt.newPkgs)
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRemovePackages(t *testing.T) {
+	existing := []PackageConfig{
+		{Name: "node", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "main"},
+		{Name: "node", InstalledBy: "nvm"},
+		{Name: "python", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "main"},
+		{Name: "python", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "versions"},
+	}
+
+	got := RemovePackages(existing, []PackageConfig{
+		{Name: "node", InstalledBy: pkgmgr.ManagerTypeScoop},
+		{Name: "python", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "versions"},
+	})
+
+	require.Equal(t, []PackageConfig{
+		{Name: "node", InstalledBy: "nvm"},
+		{Name: "python", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "main"},
+	}, got)
+}
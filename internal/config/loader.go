@@ -1,17 +1,37 @@
 package config
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+
+	"devctl/pkg/codec"
+	"devctl/pkg/fileutil"
 )
 
-// LoadFromFile loads configuration from a JSON file.
+// fileExtensions are the supported configuration file extensions in order of precedence.
+var fileExtensions = []string{".json", ".yaml", ".yml", ".toml"}
+
+// FilePath returns the path of the configuration file in configDir.
+// The format is chosen by extension: the first existing devctl.json,
+// devctl.yaml, devctl.yml or devctl.toml is used, devctl.json otherwise.
+func FilePath(configDir string) string {
+	for _, ext := range fileExtensions {
+		p := filepath.Join(configDir, AppName+ext)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return filepath.Join(configDir, AppName+fileExtensions[0])
+}
+
+// LoadFromFile loads configuration from the configuration file in configDir.
 // Returns nil if file doesn't exist (not an error - allows fallback to defaults).
 // Returns error only for actual read/parse failures.
 func LoadFromFile(configDir string) (*Config, error) {
-	configPath := filepath.Join(configDir, fmt.Sprintf("%s.json", AppName))
+	configPath := FilePath(configDir)
 
 	// If config file doesn't exist, return nil (not an error)
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
@@ -24,29 +44,49 @@ func LoadFromFile(configDir string) (*Config, error) {
 	}
 
 	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	if err := codec.ForPath(configPath).Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
 	return &cfg, nil
 }
 
-// SaveToFile saves configuration to a JSON file.
+// SaveToFile saves configuration to the configuration file in configDir,
+// keeping the format of an existing file. The file is replaced atomically
+// and the previous version is kept next to it with a .bak suffix.
 func SaveToFile(cfg *Config, configDir string) error {
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	configPath := filepath.Join(configDir, fmt.Sprintf("%s.json", AppName))
+	configPath := FilePath(configDir)
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	data, err := codec.ForPath(configPath).Marshal(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	perm := os.FileMode(0644)
+	previous, err := os.ReadFile(configPath)
+	switch {
+	case err == nil:
+		if info, err := os.Stat(configPath); err == nil {
+			perm = info.Mode().Perm()
+		}
+		if err := fileutil.WriteFileAtomic(configPath+BackupSuffix, previous, perm); err != nil {
+			return fmt.Errorf("failed to back up config file: %w", err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := fileutil.WriteFileAtomic(configPath, data, perm); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
 	return nil
 }
+
+// BackupSuffix is appended to the path of the configuration file to get the
+// path of the copy of its previous version.
+const BackupSuffix = ".bak"
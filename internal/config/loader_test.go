@@ -93,3 +93,24 @@ func TestSaveToFile(t *testing.T) {
 		assert.Equal(t, "/new", loadedCfg.DataDir)
 	})
 }
+
+func TestSaveToFileBackup(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "devctl.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"dataDir": "/old"}`), 0600))
+
+	err := SaveToFile(&Config{DataDir: "/new"}, tempDir)
+
+	require.NoError(t, err)
+	backup, err := os.ReadFile(configPath + BackupSuffix)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"dataDir": "/old"}`, string(backup))
+
+	info, err := os.Stat(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	entries, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "temporary files must be cleaned up")
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gofrs/flock"
+)
+
+// ErrLocked is returned by Lock when another devctl process holds the lock
+// of the configuration file.
+var ErrLocked = errors.New("the configuration file is in use by another devctl process, try again when it has finished")
+
+// LockPath returns the path of the lock file of the configuration in configDir.
+func LockPath(configDir string) string {
+	return filepath.Join(configDir, AppName+".lock")
+}
+
+// Lock takes the advisory lock of the configuration file in cfg.ConfigDir and
+// reloads the file into cfg, so that a read-modify-write started after Lock
+// does not overwrite changes saved by another process in the meantime.
+// It returns ErrLocked without waiting if the lock is held. The returned
+// function releases the lock.
+func Lock(cfg *Config) (func(), error) {
+	if err := os.MkdirAll(cfg.ConfigDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	fl := flock.New(LockPath(cfg.ConfigDir))
+	locked, err := fl.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock config file: %w", err)
+	}
+	if !locked {
+		return nil, ErrLocked
+	}
+
+	fileCfg, err := LoadFromFile(cfg.ConfigDir)
+	if err != nil {
+		_ = fl.Unlock()
+		return nil, err
+	}
+	if fileCfg == nil {
+		fileCfg = &Config{}
+	}
+	cfg.merge(fileCfg)
+	// Packages and package managers only come from the file, so they are
+	// replaced even if the file no longer has any.
+	cfg.PackageManagers = fileCfg.PackageManagers
+	cfg.Packages = fileCfg.Packages
+
+	return func() { _ = fl.Unlock() }, nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"devctl/pkg/pkgmgr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	configDir := t.TempDir()
+	cfg := &Config{ConfigDir: configDir}
+
+	unlock, err := Lock(cfg)
+	require.NoError(t, err)
+
+	_, err = Lock(&Config{ConfigDir: configDir})
+	require.ErrorIs(t, err, ErrLocked)
+
+	unlock()
+
+	unlock, err = Lock(&Config{ConfigDir: configDir})
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestLockReloads(t *testing.T) {
+	configDir := t.TempDir()
+	cfg := &Config{
+		ConfigDir: configDir,
+		Packages:  []PackageConfig{{Name: "git", InstalledBy: pkgmgr.ManagerTypeBrew}},
+	}
+	data := `{"packages": [{"name": "jq", "installedBy": "brew"}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "devctl.json"), []byte(data), 0644))
+
+	unlock, err := Lock(cfg)
+	require.NoError(t, err)
+	defer unlock()
+
+	require.Equal(t, []PackageConfig{{Name: "jq", InstalledBy: pkgmgr.ManagerTypeBrew}}, cfg.Packages)
+}
+
+func TestLockReloadsEmptiedFile(t *testing.T) {
+	configDir := t.TempDir()
+	cfg := &Config{
+		ConfigDir:       configDir,
+		PackageManagers: map[pkgmgr.ManagerType]PackageManagerConfig{pkgmgr.ManagerTypeBrew: {}},
+		Packages:        []PackageConfig{{Name: "git", InstalledBy: pkgmgr.ManagerTypeBrew}},
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "devctl.json"), []byte(`{}`), 0644))
+
+	unlock, err := Lock(cfg)
+	require.NoError(t, err)
+	defer unlock()
+
+	require.Empty(t, cfg.PackageManagers)
+	require.Empty(t, cfg.Packages)
+}
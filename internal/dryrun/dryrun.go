@@ -0,0 +1,199 @@
+// Package dryrun records mutating operations instead of executing them.
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"devctl/pkg/pkgmgr"
+)
+
+// Recorder collects the operations skipped because of --dry-run.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu  sync.Mutex
+	ops []string
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Record adds an operation to the recorder.
+func (r *Recorder) Record(op string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ops = append(r.ops, op)
+}
+
+// RecordCommand adds a command line to the recorder.
+func (r *Recorder) RecordCommand(cmdline []string) {
+	r.Record(FormatCommand(cmdline))
+}
+
+// RecordWrite adds a file write to the recorder.
+func (r *Recorder) RecordWrite(path string) {
+	r.Record("write " + path)
+}
+
+// Operations returns the recorded operations in the order they were recorded.
+func (r *Recorder) Operations() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.ops...)
+}
+
+// Print writes the recorded operations to w.
+// Nothing is written if no operation was recorded.
+func (r *Recorder) Print(w io.Writer) {
+	ops := r.Operations()
+	if len(ops) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\nDry run: the following operations were not executed:")
+	for _, op := range ops {
+		fmt.Fprintf(w, "  %s\n", op)
+	}
+}
+
+// FormatCommand formats a command line for display, quoting arguments
+// that contain whitespace or quotes.
+func FormatCommand(cmdline []string) string {
+	parts := make([]string, 0, len(cmdline))
+	for _, arg := range cmdline {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+			arg = strconv.Quote(arg)
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Manager wraps a pkgmgr.Manager so that Install and Uninstall are recorded
+// instead of executed. List is read-only and still runs.
+type Manager struct {
+	inner       pkgmgr.Manager
+	managerType pkgmgr.ManagerType
+	rec         *Recorder
+}
+
+// NewManager wraps inner, recording its mutating operations in rec.
+func NewManager(managerType pkgmgr.ManagerType, inner pkgmgr.Manager, rec *Recorder) *Manager {
+	return &Manager{
+		inner:       inner,
+		managerType: managerType,
+		rec:         rec,
+	}
+}
+
+// Install records the command line that would install names.
+func (m *Manager) Install(_ context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	if d, ok := m.inner.(pkgmgr.CommandDescriber); ok {
+		m.rec.RecordCommand(d.InstallCommand(names...))
+		return nil
+	}
+	m.rec.RecordCommand(append([]string{string(m.managerType), "install"}, names...))
+	return nil
+}
+
+// Uninstall records the command line that would uninstall names.
+func (m *Manager) Uninstall(_ context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	if d, ok := m.inner.(pkgmgr.CommandDescriber); ok {
+		m.rec.RecordCommand(d.UninstallCommand(names...))
+		return nil
+	}
+	m.rec.RecordCommand(append([]string{string(m.managerType), "uninstall"}, names...))
+	return nil
+}
+
+// Upgrade records the command line that would upgrade names.
+// It returns pkgmgr.ErrUnsupported if the wrapped manager cannot upgrade in place.
+func (m *Manager) Upgrade(_ context.Context, names ...string) error {
+	u, ok := m.inner.(pkgmgr.Upgrader)
+	if !ok {
+		return pkgmgr.ErrUnsupported
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	m.rec.RecordCommand(u.UpgradeCommand(names...))
+	return nil
+}
+
+// UpgradeCommand returns the command line the wrapped manager would run to upgrade names.
+func (m *Manager) UpgradeCommand(names ...string) []string {
+	if u, ok := m.inner.(pkgmgr.Upgrader); ok {
+		return u.UpgradeCommand(names...)
+	}
+	return append([]string{string(m.managerType), "upgrade"}, names...)
+}
+
+// SwitchVersion records the command line that would switch name from one version to another.
+// It returns pkgmgr.ErrUnsupported if the wrapped manager cannot switch versions.
+func (m *Manager) SwitchVersion(_ context.Context, name, from, to string) error {
+	s, ok := m.inner.(pkgmgr.VersionSwitcher)
+	if !ok {
+		return pkgmgr.ErrUnsupported
+	}
+	m.rec.RecordCommand(s.SwitchVersionCommand(name, from, to))
+	return nil
+}
+
+// SwitchVersionCommand returns the command line the wrapped manager would run to switch versions.
+func (m *Manager) SwitchVersionCommand(name, from, to string) []string {
+	if s, ok := m.inner.(pkgmgr.VersionSwitcher); ok {
+		return s.SwitchVersionCommand(name, from, to)
+	}
+	return []string{string(m.managerType), "install", name + "@" + to}
+}
+
+// List returns the packages listed by the wrapped manager.
+func (m *Manager) List(ctx context.Context) ([]pkgmgr.Package, error) {
+	return m.inner.List(ctx)
+}
+
+// Outdated returns the outdated packages reported by the wrapped manager.
+// It returns pkgmgr.ErrUnsupported if the wrapped manager cannot report them.
+func (m *Manager) Outdated(ctx context.Context) ([]pkgmgr.OutdatedPackage, error) {
+	o, ok := m.inner.(pkgmgr.OutdatedLister)
+	if !ok {
+		return nil, pkgmgr.ErrUnsupported
+	}
+	return o.Outdated(ctx)
+}
+
+// Installer wraps a pkgmgr.Installer so that Install is recorded instead of executed.
+type Installer struct {
+	pkgmgr.Installer
+	rec *Recorder
+}
+
+// NewInstaller wraps inner, recording its installation command in rec.
+func NewInstaller(inner pkgmgr.Installer, rec *Recorder) *Installer {
+	return &Installer{
+		Installer: inner,
+		rec:       rec,
+	}
+}
+
+// Install records the installation command and reports completion without running it.
+func (i *Installer) Install(_ context.Context, progress chan<- pkgmgr.InstallProgress) error {
+	i.rec.Record(i.GetInstallCommand())
+	progress <- pkgmgr.InstallProgress{
+		Stage:   "complete",
+		Message: "Dry run: installation skipped",
+		Percent: 100,
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package dryrun
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"devctl/pkg/pkgmgr"
+	"devctl/pkg/pkgmgr/scoop"
+
+	"github.com/stretchr/testify/require"
+)
+
+type plainManager struct {
+	calls int
+}
+
+func (m *plainManager) Install(context.Context, ...string) error   { m.calls++; return nil }
+func (m *plainManager) Uninstall(context.Context, ...string) error { m.calls++; return nil }
+func (m *plainManager) List(context.Context) ([]pkgmgr.Package, error) {
+	return []pkgmgr.Package{{Name: "git", Version: "2.43.0"}}, nil
+}
+
+func TestManagerRecordsExactCommandLine(t *testing.T) {
+	rec := NewRecorder()
+	mgr := NewManager(pkgmgr.ManagerTypeScoop, scoop.New(nil), rec)
+	ctx := context.Background()
+
+	require.NoError(t, mgr.Install(ctx, "git@2.43.0"))
+	require.NoError(t, mgr.Uninstall(ctx, "7zip"))
+
+	require.Equal(t, []string{
+		"scoop install git@2.43.0",
+		"scoop uninstall 7zip",
+	}, rec.Operations())
+}
+
+func TestManagerWithoutDescriber(t *testing.T) {
+	rec := NewRecorder()
+	inner := &plainManager{}
+	mgr := NewManager("in-house", inner, rec)
+	ctx := context.Background()
+
+	require.NoError(t, mgr.Install(ctx, "tool"))
+	pkgs, err := mgr.List(ctx)
+
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+	require.Zero(t, inner.calls)
+	require.Equal(t, []string{"in-house install tool"}, rec.Operations())
+}
+
+func TestFormatCommand(t *testing.T) {
+	require.Equal(t, `pwsh -Command "Install-Module -Name 'Az'"`, FormatCommand([]string{"pwsh", "-Command", "Install-Module -Name 'Az'"}))
+}
+
+func TestPrint(t *testing.T) {
+	var out strings.Builder
+	rec := NewRecorder()
+
+	rec.Print(&out)
+	require.Empty(t, out.String())
+
+	rec.RecordWrite("/home/user/.config/devctl/devctl.json")
+	rec.Print(&out)
+	require.Contains(t, out.String(), "  write /home/user/.config/devctl/devctl.json\n")
+}
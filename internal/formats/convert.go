@@ -7,6 +7,7 @@ func (p *PackageFormat) ToConfig() config.PackageConfig {
 		Name:        p.Name,
 		Version:     p.Version,
 		InstalledBy: p.InstalledBy,
+		Repository:  p.Repository,
 	}
 }
 
@@ -15,5 +16,6 @@ func FromConfig(cfg config.PackageConfig) PackageFormat {
 		Name:        cfg.Name,
 		Version:     cfg.Version,
 		InstalledBy: cfg.InstalledBy,
+		Repository:  cfg.Repository,
 	}
 }
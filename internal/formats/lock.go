@@ -0,0 +1,190 @@
+package formats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"devctl/internal/config"
+	"devctl/pkg/pkgmgr"
+)
+
+// LockFileSuffix replaces the extension of a manifest to name its lockfile,
+// e.g. "devctl.lock.json" for "devctl.yaml".
+const LockFileSuffix = ".lock.json"
+
+// LockSchemaVersion is the lockfile schema version written by this version of devctl.
+const LockSchemaVersion = 1
+
+// LockFile records the exact packages a manifest resolved to. Each platform
+// has its own section, so one lockfile can accompany a cross-platform manifest.
+type LockFile struct {
+	SchemaVersion int                      `json:"schemaVersion"`
+	Platforms     map[string]*PlatformLock `json:"platforms"`
+}
+
+// PlatformLock is the resolved manifest of one platform.
+type PlatformLock struct {
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Packages    []LockedPackage `json:"packages"`
+}
+
+// LockedPackage is a manifest entry resolved to an exact version.
+type LockedPackage struct {
+	Name string `json:"name"`
+	// Requested is the version requested by the manifest. Empty if the
+	// manifest does not request a version.
+	Requested   string             `json:"requested,omitempty"`
+	Version     string             `json:"version"`
+	InstalledBy pkgmgr.ManagerType `json:"installedBy"`
+	// Repository is the bucket, tap or repository the package comes from.
+	Repository string `json:"repository,omitempty"`
+}
+
+// LockFilePath returns the path of the lockfile for a manifest. Every
+// manifest has its own lockfile next to it, named after the manifest.
+func LockFilePath(manifestPath string) string {
+	return strings.TrimSuffix(manifestPath, filepath.Ext(manifestPath)) + LockFileSuffix
+}
+
+// Validate validates the lockfile.
+func (l *LockFile) Validate() error {
+	if l.SchemaVersion > LockSchemaVersion {
+		return fmt.Errorf("lockfile schema version %d is not supported (newest supported version is %d), please upgrade devctl", l.SchemaVersion, LockSchemaVersion)
+	}
+	for platform, pl := range l.Platforms {
+		if pl == nil {
+			return fmt.Errorf("%s: missing packages", platform)
+		}
+		for i, pkg := range pl.Packages {
+			if pkg.Name == "" {
+				return fmt.Errorf("%s: package[%d]: package name is required", platform, i)
+			}
+			if pkg.Version == "" {
+				return fmt.Errorf("%s: package[%d]: package version is required", platform, i)
+			}
+			if pkg.InstalledBy == "" {
+				return fmt.Errorf("%s: package[%d]: installedBy is required", platform, i)
+			}
+		}
+	}
+	return nil
+}
+
+// Lock records the resolved packages of platform, replacing any previous
+// section of that platform. Packages are sorted by manager, repository and
+// name.
+func (l *LockFile) Lock(platform string, packages []LockedPackage, now time.Time) {
+	packages = append([]LockedPackage{}, packages...)
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].InstalledBy != packages[j].InstalledBy {
+			return packages[i].InstalledBy < packages[j].InstalledBy
+		}
+		if packages[i].Repository != packages[j].Repository {
+			return packages[i].Repository < packages[j].Repository
+		}
+		return packages[i].Name < packages[j].Name
+	})
+
+	if l.Platforms == nil {
+		l.Platforms = map[string]*PlatformLock{}
+	}
+	l.SchemaVersion = LockSchemaVersion
+	l.Platforms[platform] = &PlatformLock{
+		GeneratedAt: now.UTC(),
+		Packages:    packages,
+	}
+}
+
+// Resolve returns the desired packages of platform pinned to their locked
+// versions. It fails if the lockfile is stale, that is if the lockfile does
+// not have exactly the desired packages with the requested versions.
+func (l *LockFile) Resolve(platform string, desired []config.PackageConfig) ([]config.PackageConfig, error) {
+	pl := l.Platforms[platform]
+	if pl == nil {
+		return nil, fmt.Errorf("lockfile has no packages for platform '%s', run 'devctl lock'", platform)
+	}
+
+	used := make([]bool, len(pl.Packages))
+	find := func(pkg config.PackageConfig) int {
+		for i, lp := range pl.Packages {
+			locked := config.PackageConfig{Name: lp.Name, InstalledBy: lp.InstalledBy, Repository: lp.Repository}
+			if !used[i] && config.SamePackage(pkg, locked) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var problems []string
+	resolved := make([]config.PackageConfig, 0, len(desired))
+	for _, pkg := range desired {
+		i := find(pkg)
+		if i < 0 {
+			problems = append(problems, fmt.Sprintf("%s %s is not locked", pkg.InstalledBy, pkg.Name))
+			continue
+		}
+		used[i] = true
+		lp := pl.Packages[i]
+		if lp.Requested != pkg.Version {
+			problems = append(problems, fmt.Sprintf("%s %s was locked for version %q, manifest requests %q", pkg.InstalledBy, pkg.Name, lp.Requested, pkg.Version))
+			continue
+		}
+		pkg.Version = lp.Version
+		resolved = append(resolved, pkg)
+	}
+	for i, lp := range pl.Packages {
+		if !used[i] {
+			problems = append(problems, fmt.Sprintf("%s %s is locked but not in the manifest", lp.InstalledBy, lp.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("lockfile is stale, run 'devctl lock':\n  %s", strings.Join(problems, "\n  "))
+	}
+	return resolved, nil
+}
+
+// LoadLockFile reads a lockfile.
+func LoadLockFile(filePath string) (*LockFile, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	var l LockFile
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile: %w", err)
+	}
+
+	if err := l.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid lockfile: %w", err)
+	}
+
+	return &l, nil
+}
+
+// SaveLockFile writes a lockfile.
+func SaveLockFile(filePath string, l *LockFile) error {
+	if l == nil {
+		return fmt.Errorf("missing lockfile")
+	}
+	if err := l.Validate(); err != nil {
+		return fmt.Errorf("invalid lockfile: %w", err)
+	}
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal lockfile: %w", err)
+	}
+
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+
+	return nil
+}
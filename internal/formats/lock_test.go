@@ -0,0 +1,152 @@
+package formats
+
+import (
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"devctl/internal/config"
+	"devctl/pkg/pkgmgr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockFileLock(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	l := &LockFile{Platforms: map[string]*PlatformLock{
+		"darwin": {GeneratedAt: now, Packages: []LockedPackage{{Name: "wget", Version: "1.21.4", InstalledBy: pkgmgr.ManagerTypeBrew}}},
+	}}
+
+	l.Lock("windows", []LockedPackage{
+		{Name: "git", Version: "2.44.0", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "main"},
+		{Name: "PSReadLine", Version: "2.3.4", InstalledBy: pkgmgr.ManagerTypePwsh, Repository: "PSGallery"},
+		{Name: "7zip", Version: "23.01", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "main"},
+	}, now)
+
+	require.Equal(t, LockSchemaVersion, l.SchemaVersion)
+	require.Contains(t, l.Platforms, "darwin")
+	windows := l.Platforms["windows"]
+	require.Equal(t, now.UTC(), windows.GeneratedAt)
+	require.Equal(t, []string{"PSReadLine", "7zip", "git"}, []string{
+		windows.Packages[0].Name, windows.Packages[1].Name, windows.Packages[2].Name,
+	})
+}
+
+func TestLockFileResolve(t *testing.T) {
+	scoop := pkgmgr.ManagerTypeScoop
+	l := &LockFile{}
+	l.Lock("windows", []LockedPackage{
+		{Name: "git", Requested: "2.44.0", Version: "2.44.0", InstalledBy: scoop},
+		{Name: "7zip", Requested: "23.01", Version: "23.01", InstalledBy: scoop},
+	}, time.Now())
+
+	tests := []struct {
+		name     string
+		platform string
+		desired  []config.PackageConfig
+		want     []config.PackageConfig
+		wantErr  string
+	}{
+		{
+			name:     "up to date",
+			platform: "windows",
+			desired: []config.PackageConfig{
+				{Name: "git", Version: "2.44.0", InstalledBy: scoop},
+				{Name: "7zip", Version: "23.01", InstalledBy: scoop},
+			},
+			want: []config.PackageConfig{
+				{Name: "git", Version: "2.44.0", InstalledBy: scoop},
+				{Name: "7zip", Version: "23.01", InstalledBy: scoop},
+			},
+		},
+		{
+			name:     "other platform",
+			platform: "linux",
+			wantErr:  "lockfile has no packages for platform 'linux'",
+		},
+		{
+			name:     "version changed",
+			platform: "windows",
+			desired: []config.PackageConfig{
+				{Name: "git", Version: "2.45.0", InstalledBy: scoop},
+				{Name: "7zip", Version: "23.01", InstalledBy: scoop},
+			},
+			wantErr: `scoop git was locked for version "2.44.0", manifest requests "2.45.0"`,
+		},
+		{
+			name:     "package added",
+			platform: "windows",
+			desired: []config.PackageConfig{
+				{Name: "git", Version: "2.44.0", InstalledBy: scoop},
+				{Name: "7zip", Version: "23.01", InstalledBy: scoop},
+				{Name: "jq", Version: "1.7.1", InstalledBy: scoop},
+			},
+			wantErr: "scoop jq is not locked",
+		},
+		{
+			name:     "package removed",
+			platform: "windows",
+			desired: []config.PackageConfig{
+				{Name: "git", Version: "2.44.0", InstalledBy: scoop},
+			},
+			wantErr: "scoop 7zip is locked but not in the manifest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := l.Resolve(tt.platform, tt.desired)
+
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSaveAndLoadLockFile(t *testing.T) {
+	path := LockFilePath(filepath.Join(t.TempDir(), "devctl.yaml"))
+	l := &LockFile{}
+	l.Lock("linux", []LockedPackage{
+		{Name: "jq", Version: "1.7.1-3build1", InstalledBy: pkgmgr.ManagerTypeApt},
+	}, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	require.NoError(t, SaveLockFile(path, l))
+	got, err := LoadLockFile(path)
+
+	require.NoError(t, err)
+	require.Equal(t, l, got)
+	require.Equal(t, "devctl.lock.json", filepath.Base(path))
+}
+
+func TestLockFilePerManifest(t *testing.T) {
+	dir := t.TempDir()
+	work := LockFilePath(filepath.Join(dir, "work.yaml"))
+	home := LockFilePath(filepath.Join(dir, "home.json"))
+	require.Equal(t, filepath.Join(dir, "work.lock.json"), work)
+	require.Equal(t, filepath.Join(dir, "home.lock.json"), home)
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	workLock := &LockFile{}
+	workLock.Lock("linux", []LockedPackage{{Name: "jq", Version: "1.7.1-3build1", InstalledBy: pkgmgr.ManagerTypeApt}}, now)
+	homeLock := &LockFile{}
+	homeLock.Lock("linux", []LockedPackage{{Name: "curl", Version: "8.5.0-2ubuntu10.1", InstalledBy: pkgmgr.ManagerTypeApt}}, now)
+	require.NoError(t, SaveLockFile(work, workLock))
+	require.NoError(t, SaveLockFile(home, homeLock))
+
+	got, err := LoadLockFile(work)
+	require.NoError(t, err)
+	resolved, err := got.Resolve("linux", []config.PackageConfig{{Name: "jq", InstalledBy: pkgmgr.ManagerTypeApt}})
+	require.NoError(t, err)
+	require.Equal(t, "1.7.1-3build1", resolved[0].Version)
+}
+
+func TestLoadLockFileNotExist(t *testing.T) {
+	_, err := LoadLockFile(filepath.Join(t.TempDir(), "devctl.lock.json"))
+
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -1,16 +1,17 @@
 package formats
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+
+	"devctl/pkg/codec"
 )
 
 type ManifestFile struct {
-	Platform string          `json:"platform,omitempty"`
-	Packages []PackageFormat `json:"packages"`
+	Platform string          `json:"platform,omitempty" yaml:"platform,omitempty" toml:"platform,omitempty"`
+	Packages []PackageFormat `json:"packages" yaml:"packages" toml:"packages"`
 }
 
 func (f *ManifestFile) Validate() error {
@@ -36,28 +37,43 @@ func (f *ManifestFile) IsCompatibleWithCurrentPlatform() bool {
 	return f.Platform == runtime.GOOS
 }
 
+// LoadManifestFile loads a manifest and resolves it to the packages of the
+// current platform. Manifests of older schema versions are migrated first.
 func LoadManifestFile(filePath string) (*ManifestFile, error) {
-	data, err := os.ReadFile(filePath)
+	toolFile, _, err := LoadToolManifestFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, err
 	}
 
-	var importFile ManifestFile
-	if err := json.Unmarshal(data, &importFile); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	importFile := toolFile.ForPlatform(runtime.GOOS)
+	if len(importFile.Packages) == 0 {
+		return nil, fmt.Errorf("manifest has no packages for platform '%s'", runtime.GOOS)
 	}
 
-	if err := importFile.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid format: %w", err)
+	return importFile, nil
+}
+
+// LoadToolManifestFile loads a manifest and migrates it to the current schema version.
+// It returns the manifest and the schema version of the file.
+func LoadToolManifestFile(filePath string) (*ToolManifestFile, int, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	toolFile, version, err := Migrate(data, codec.ForPath(filePath))
+	if err != nil {
+		return nil, version, err
 	}
 
-	if !importFile.IsCompatibleWithCurrentPlatform() {
-		return nil, fmt.Errorf("import file is for platform '%s', but current platform is '%s'", importFile.Platform, runtime.GOOS)
+	if err := toolFile.Validate(); err != nil {
+		return nil, version, fmt.Errorf("invalid format: %w", err)
 	}
 
-	return &importFile, nil
+	return toolFile, version, nil
 }
 
+// SaveManifestFile saves a platform manifest in the current schema version.
 func SaveManifestFile(filePath string, f *ManifestFile) error {
 	if f == nil {
 		return fmt.Errorf("missing import file")
@@ -66,9 +82,23 @@ func SaveManifestFile(filePath string, f *ManifestFile) error {
 		return fmt.Errorf("invalid format: %w", err)
 	}
 
-	data, err := json.MarshalIndent(f, "", "  ")
+	return SaveToolManifestFile(filePath, ToolsFromManifest(f))
+}
+
+// SaveToolManifestFile saves a manifest in the current schema version.
+func SaveToolManifestFile(filePath string, f *ToolManifestFile) error {
+	if f == nil {
+		return fmt.Errorf("missing import file")
+	}
+	f.SchemaVersion = CurrentSchemaVersion
+	if err := f.Validate(); err != nil {
+		return fmt.Errorf("invalid format: %w", err)
+	}
+
+	c := codec.ForPath(filePath)
+	data, err := c.Marshal(f)
 	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+		return fmt.Errorf("failed to marshal %s: %w", c.Format(), err)
 	}
 
 	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
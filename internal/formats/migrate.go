@@ -0,0 +1,111 @@
+package formats
+
+import (
+	"fmt"
+
+	"devctl/pkg/codec"
+)
+
+// CurrentSchemaVersion is the manifest schema version written by this version of devctl.
+//
+// Schema versions:
+//
+//	1: a single platform manifest with "platform" and "packages".
+//	   Version 1 files have no "schemaVersion" field.
+//	2: a cross-platform manifest with "tools".
+const CurrentSchemaVersion = 2
+
+// migrations upgrade an encoded manifest from version i+1 to version i+2,
+// keeping its encoding.
+var migrations = []func(data []byte, c codec.Codec) ([]byte, error){
+	migrateV1ToV2,
+}
+
+// UnsupportedSchemaVersionError is returned for manifests written by a newer devctl.
+type UnsupportedSchemaVersionError struct {
+	Version int
+}
+
+func (e *UnsupportedSchemaVersionError) Error() string {
+	return fmt.Sprintf("manifest schema version %d is not supported (newest supported version is %d), please upgrade devctl", e.Version, CurrentSchemaVersion)
+}
+
+// Migrate decodes a manifest and upgrades it to the current schema version.
+// It returns the upgraded manifest and the schema version of data.
+func Migrate(data []byte, c codec.Codec) (*ToolManifestFile, int, error) {
+	var doc map[string]any
+	if err := c.Unmarshal(data, &doc); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse %s: %w", c.Format(), err)
+	}
+
+	from, err := schemaVersion(doc)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for v := from; v < CurrentSchemaVersion; v++ {
+		data, err = migrations[v-1](data, c)
+		if err != nil {
+			return nil, from, fmt.Errorf("failed to migrate schema version %d to %d: %w", v, v+1, err)
+		}
+	}
+
+	var f ToolManifestFile
+	if err := c.Unmarshal(data, &f); err != nil {
+		return nil, from, fmt.Errorf("failed to parse %s: %w", c.Format(), err)
+	}
+	f.SchemaVersion = CurrentSchemaVersion
+	return &f, from, nil
+}
+
+// schemaVersion returns the schema version of a manifest document.
+// Documents without a version are detected by their shape.
+func schemaVersion(doc map[string]any) (int, error) {
+	raw, ok := doc["schemaVersion"]
+	if !ok {
+		if _, ok := doc["tools"]; ok {
+			return 2, nil
+		}
+		return 1, nil
+	}
+
+	var n int
+	switch v := raw.(type) {
+	case int:
+		n = v
+	case int64:
+		n = int(v)
+	case uint64:
+		n = int(v)
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("invalid schemaVersion %v", raw)
+		}
+		n = int(v)
+	default:
+		return 0, fmt.Errorf("invalid schemaVersion %v", raw)
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("invalid schemaVersion %v", raw)
+	}
+	if n > CurrentSchemaVersion {
+		return 0, &UnsupportedSchemaVersionError{Version: n}
+	}
+	return n, nil
+}
+
+// migrateV1ToV2 turns every package of a platform manifest into a tool that
+// is only available on that platform.
+func migrateV1ToV2(data []byte, c codec.Codec) ([]byte, error) {
+	var f ManifestFile
+	if err := c.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if f.Platform == "" {
+		return nil, fmt.Errorf("missing platform")
+	}
+
+	tools := ToolsFromManifest(&f)
+	tools.SchemaVersion = 2
+	return c.Marshal(tools)
+}
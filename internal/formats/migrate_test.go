@@ -0,0 +1,113 @@
+package formats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"devctl/pkg/codec"
+	"devctl/pkg/pkgmgr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantVersion int
+		wantTools   []Tool
+		wantErr     string
+	}{
+		{
+			name:        "version 1 platform manifest",
+			data:        `{"platform":"windows","packages":[{"name":"git","version":"2.44.0","installedBy":"scoop"},{"name":"git","version":"2.4.2","installedBy":"pwsh"}]}`,
+			wantVersion: 1,
+			wantTools: []Tool{
+				{Name: "git", Version: "2.44.0", Platforms: map[string]ToolMapping{
+					"windows": {InstalledBy: pkgmgr.ManagerTypeScoop},
+				}},
+				{Name: "pwsh:git", Version: "2.4.2", Platforms: map[string]ToolMapping{
+					"windows": {Name: "git", InstalledBy: pkgmgr.ManagerTypePwsh},
+				}},
+			},
+		},
+		{
+			name:        "unversioned tool manifest",
+			data:        `{"tools":[{"name":"jq@1.7.1","platforms":{"darwin":{"installedBy":"brew"}}}]}`,
+			wantVersion: 2,
+			wantTools: []Tool{
+				{Name: "jq@1.7.1", Platforms: map[string]ToolMapping{
+					"darwin": {InstalledBy: pkgmgr.ManagerTypeBrew},
+				}},
+			},
+		},
+		{
+			name:        "current version",
+			data:        `{"schemaVersion":2,"tools":[{"name":"jq@1.7.1","platforms":{"darwin":{"installedBy":"brew"}}}]}`,
+			wantVersion: 2,
+			wantTools: []Tool{
+				{Name: "jq@1.7.1", Platforms: map[string]ToolMapping{
+					"darwin": {InstalledBy: pkgmgr.ManagerTypeBrew},
+				}},
+			},
+		},
+		{
+			name:    "version 1 without platform",
+			data:    `{"packages":[{"name":"git","version":"2.44.0","installedBy":"scoop"}]}`,
+			wantErr: "failed to migrate schema version 1 to 2: missing platform",
+		},
+		{
+			name:    "future version",
+			data:    `{"schemaVersion":3,"tools":[]}`,
+			wantErr: "manifest schema version 3 is not supported",
+		},
+		{
+			name:    "invalid version",
+			data:    `{"schemaVersion":"2","tools":[]}`,
+			wantErr: "invalid schemaVersion",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, version, err := Migrate([]byte(tt.data), codec.JSON)
+
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantVersion, version)
+			require.Equal(t, CurrentSchemaVersion, f.SchemaVersion)
+			require.Equal(t, tt.wantTools, f.Tools)
+		})
+	}
+}
+
+func TestMigrateFutureVersionError(t *testing.T) {
+	_, _, err := Migrate([]byte(`{"schemaVersion":7}`), codec.JSON)
+
+	var versionErr *UnsupportedSchemaVersionError
+	require.ErrorAs(t, err, &versionErr)
+	require.Equal(t, 7, versionErr.Version)
+}
+
+func TestSaveManifestFileWritesCurrentVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "export.json")
+	m := &ManifestFile{
+		Platform: "darwin",
+		Packages: []PackageFormat{{Name: "wget", Version: "1.21.4", InstalledBy: pkgmgr.ManagerTypeBrew}},
+	}
+
+	require.NoError(t, SaveManifestFile(path, m))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	f, version, err := Migrate(data, codec.JSON)
+	require.NoError(t, err)
+	require.Equal(t, CurrentSchemaVersion, version)
+	pkg, ok := f.Tools[0].Resolve("darwin")
+	require.True(t, ok)
+	require.Equal(t, m.Packages[0], pkg)
+}
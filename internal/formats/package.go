@@ -4,14 +4,21 @@ import (
 	"fmt"
 
 	"devctl/pkg/pkgmgr"
+	"devctl/pkg/version"
 )
 
 // PackageFormat defines the package format used in import/export files.
 // This is the external file format and does not include internal fields.
+// Version is a version constraint such as "1.7.1", "^1.6" or "latest",
+// and may be empty to accept any version. Repository is the bucket, tap or
+// repository of the package and may be empty to accept any repository.
+// Dependency marks packages that were only installed to satisfy another package.
 type PackageFormat struct {
-	Name        string             `json:"name"`
-	Version     string             `json:"version"`
-	InstalledBy pkgmgr.ManagerType `json:"installedBy"`
+	Name        string             `json:"name" yaml:"name" toml:"name"`
+	Version     string             `json:"version" yaml:"version" toml:"version"`
+	InstalledBy pkgmgr.ManagerType `json:"installedBy" yaml:"installedBy" toml:"installedBy"`
+	Repository  string             `json:"repository,omitempty" yaml:"repository,omitempty" toml:"repository,omitempty"`
+	Dependency  bool               `json:"dependency,omitempty" yaml:"dependency,omitempty" toml:"dependency,omitempty"`
 }
 
 // Validate validates the package format.
@@ -19,8 +26,8 @@ func (p *PackageFormat) Validate() error {
 	if p.Name == "" {
 		return fmt.Errorf("package name is required")
 	}
-	if p.Version == "" {
-		return fmt.Errorf("package version is required")
+	if _, err := version.ParseConstraint(p.Version); err != nil {
+		return err
 	}
 	if p.InstalledBy == "" {
 		return fmt.Errorf("installedBy is required")
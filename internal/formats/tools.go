@@ -0,0 +1,168 @@
+package formats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"devctl/pkg/pkgmgr"
+)
+
+// ToolManifestFile is a cross-platform manifest. Each tool is a logical entry
+// that maps to a package and package manager per platform, so one file can be
+// imported on any operating system.
+type ToolManifestFile struct {
+	SchemaVersion int    `json:"schemaVersion" yaml:"schemaVersion" toml:"schemaVersion"`
+	Tools         []Tool `json:"tools" yaml:"tools" toml:"tools"`
+}
+
+// Tool is a logical tool, e.g. "git" or "node@20". The version after "@" is
+// the default version for every platform. Names that contain "@" end with
+// "@", e.g. "python@3.12@", and take their version from the Version field.
+type Tool struct {
+	Name      string                 `json:"name" yaml:"name" toml:"name"`
+	Version   string                 `json:"version,omitempty" yaml:"version,omitempty" toml:"version,omitempty"`
+	Platforms map[string]ToolMapping `json:"platforms" yaml:"platforms" toml:"platforms"`
+}
+
+// ToolMapping is the package that provides a tool on one platform.
+type ToolMapping struct {
+	// Name is the package name. If empty, defaults to the tool name.
+	Name string `json:"name,omitempty" yaml:"name,omitempty" toml:"name,omitempty"`
+	// Version is the package version. If empty, defaults to the tool version.
+	Version     string             `json:"version,omitempty" yaml:"version,omitempty" toml:"version,omitempty"`
+	InstalledBy pkgmgr.ManagerType `json:"installedBy" yaml:"installedBy" toml:"installedBy"`
+	// Repository is the bucket, tap or repository of the package. If empty,
+	// the package is accepted from any repository.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty" toml:"repository,omitempty"`
+	// Dependency marks packages that were only installed to satisfy another package.
+	Dependency bool `json:"dependency,omitempty" yaml:"dependency,omitempty" toml:"dependency,omitempty"`
+}
+
+// Validate validates the manifest.
+func (f *ToolManifestFile) Validate() error {
+	if len(f.Tools) == 0 {
+		return fmt.Errorf("no tools specified")
+	}
+
+	seen := map[string]bool{}
+	for i, tool := range f.Tools {
+		name, _ := tool.split()
+		if name == "" {
+			return fmt.Errorf("tool[%d]: tool name is required", i)
+		}
+		if seen[name] {
+			return fmt.Errorf("tool[%d]: duplicate tool %q", i, name)
+		}
+		seen[name] = true
+		if len(tool.Platforms) == 0 {
+			return fmt.Errorf("tool[%d]: no platforms specified", i)
+		}
+
+		platforms := make([]string, 0, len(tool.Platforms))
+		for platform := range tool.Platforms {
+			platforms = append(platforms, platform)
+		}
+		sort.Strings(platforms)
+		for _, platform := range platforms {
+			if !isKnownPlatform(platform) {
+				return fmt.Errorf("tool[%d]: unknown platform %q", i, platform)
+			}
+			pkg, _ := tool.Resolve(platform)
+			if err := pkg.Validate(); err != nil {
+				return fmt.Errorf("tool[%d]: %s: %w", i, platform, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Resolve returns the package that provides the tool on platform.
+// Returns false if the tool is not available on platform.
+func (t *Tool) Resolve(platform string) (PackageFormat, bool) {
+	mapping, ok := t.Platforms[platform]
+	if !ok {
+		return PackageFormat{}, false
+	}
+
+	name, version := t.split()
+	if mapping.Name != "" {
+		name = mapping.Name
+	}
+	if mapping.Version != "" {
+		version = mapping.Version
+	}
+	return PackageFormat{
+		Name:        name,
+		Version:     version,
+		InstalledBy: mapping.InstalledBy,
+		Repository:  mapping.Repository,
+		Dependency:  mapping.Dependency,
+	}, true
+}
+
+// split returns the tool name and default version. A version given in
+// the "name@version" form takes precedence over the Version field.
+func (t *Tool) split() (string, string) {
+	if i := strings.LastIndex(t.Name, "@"); i > 0 {
+		if v := t.Name[i+1:]; v != "" {
+			return t.Name[:i], v
+		}
+		return t.Name[:i], t.Version
+	}
+	return t.Name, t.Version
+}
+
+// ToolsFromManifest converts a platform manifest into a cross-platform
+// manifest whose tools are only available on that platform.
+func ToolsFromManifest(m *ManifestFile) *ToolManifestFile {
+	f := &ToolManifestFile{
+		SchemaVersion: CurrentSchemaVersion,
+		Tools:         make([]Tool, 0, len(m.Packages)),
+	}
+	seen := map[string]bool{}
+	for _, pkg := range m.Packages {
+		mapping := ToolMapping{InstalledBy: pkg.InstalledBy, Repository: pkg.Repository, Dependency: pkg.Dependency}
+		name := pkg.Name
+		if seen[name] {
+			name = string(pkg.InstalledBy) + ":" + pkg.Name
+			mapping.Name = pkg.Name
+		}
+		if seen[name] && pkg.Repository != "" {
+			name = string(pkg.InstalledBy) + ":" + pkg.Repository + "/" + pkg.Name
+		}
+		seen[name] = true
+		if strings.Contains(pkg.Name, "@") {
+			// Keep split from taking "@3.12" of "python@3.12" as the version.
+			name += "@"
+		}
+
+		f.Tools = append(f.Tools, Tool{
+			Name:      name,
+			Version:   pkg.Version,
+			Platforms: map[string]ToolMapping{m.Platform: mapping},
+		})
+	}
+	return f
+}
+
+// ForPlatform returns the packages of the tools available on platform as a
+// single platform manifest. Tools without a mapping for platform are skipped.
+func (f *ToolManifestFile) ForPlatform(platform string) *ManifestFile {
+	m := &ManifestFile{Platform: platform}
+	for _, tool := range f.Tools {
+		if pkg, ok := tool.Resolve(platform); ok {
+			m.Packages = append(m.Packages, pkg)
+		}
+	}
+	return m
+}
+
+func isKnownPlatform(platform string) bool {
+	switch pkgmgr.Platform(platform) {
+	case pkgmgr.PlatformWindows, pkgmgr.PlatformDarwin, pkgmgr.PlatformLinux:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,216 @@
+package formats
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"devctl/pkg/pkgmgr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolResolve(t *testing.T) {
+	tool := Tool{
+		Name: "node@20.11.1",
+		Platforms: map[string]ToolMapping{
+			"windows": {Name: "nodejs-lts", InstalledBy: pkgmgr.ManagerTypeScoop},
+			"darwin":  {Name: "node@20", InstalledBy: pkgmgr.ManagerTypeBrew},
+			"linux":   {Name: "nodejs", Version: "20.11.1-1nodesource1", InstalledBy: pkgmgr.ManagerTypeApt},
+		},
+	}
+
+	tests := []struct {
+		platform string
+		want     PackageFormat
+		wantOK   bool
+	}{
+		{
+			platform: "windows",
+			want:     PackageFormat{Name: "nodejs-lts", Version: "20.11.1", InstalledBy: pkgmgr.ManagerTypeScoop},
+			wantOK:   true,
+		},
+		{
+			platform: "darwin",
+			want:     PackageFormat{Name: "node@20", Version: "20.11.1", InstalledBy: pkgmgr.ManagerTypeBrew},
+			wantOK:   true,
+		},
+		{
+			platform: "linux",
+			want:     PackageFormat{Name: "nodejs", Version: "20.11.1-1nodesource1", InstalledBy: pkgmgr.ManagerTypeApt},
+			wantOK:   true,
+		},
+		{
+			platform: "freebsd",
+			wantOK:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			got, ok := tool.Resolve(tt.platform)
+
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestToolManifestFileValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    ToolManifestFile
+		wantErr string
+	}{
+		{
+			name: "valid",
+			file: ToolManifestFile{Tools: []Tool{
+				{Name: "git", Version: "2.44.0", Platforms: map[string]ToolMapping{
+					"windows": {InstalledBy: pkgmgr.ManagerTypeScoop},
+				}},
+			}},
+		},
+		{
+			name:    "no tools",
+			file:    ToolManifestFile{},
+			wantErr: "no tools specified",
+		},
+		{
+			name: "duplicate tool",
+			file: ToolManifestFile{Tools: []Tool{
+				{Name: "git@2.44.0", Platforms: map[string]ToolMapping{"linux": {InstalledBy: pkgmgr.ManagerTypeApt}}},
+				{Name: "git@2.43.0", Platforms: map[string]ToolMapping{"linux": {InstalledBy: pkgmgr.ManagerTypeApt}}},
+			}},
+			wantErr: `duplicate tool "git"`,
+		},
+		{
+			name: "unknown platform",
+			file: ToolManifestFile{Tools: []Tool{
+				{Name: "git@2.44.0", Platforms: map[string]ToolMapping{"macos": {InstalledBy: pkgmgr.ManagerTypeBrew}}},
+			}},
+			wantErr: `unknown platform "macos"`,
+		},
+		{
+			name: "missing manager",
+			file: ToolManifestFile{Tools: []Tool{
+				{Name: "git@2.44.0", Platforms: map[string]ToolMapping{"linux": {}}},
+			}},
+			wantErr: "tool[0]: linux: installedBy is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.file.Validate()
+
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoadManifestFileWithTools(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tools.json")
+	data := `{
+  "tools": [
+    {
+      "name": "jq@1.7.1",
+      "platforms": {
+        "windows": {"installedBy": "scoop"},
+        "darwin": {"installedBy": "brew"},
+        "linux": {"installedBy": "apt", "version": "1.7.1-3build1"}
+      }
+    },
+    {
+      "name": "7zip@23.01",
+      "platforms": {
+        "windows": {"installedBy": "scoop"}
+      }
+    }
+  ]
+}`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	f, err := LoadManifestFile(path)
+
+	require.NoError(t, err)
+	require.Equal(t, runtime.GOOS, f.Platform)
+	switch runtime.GOOS {
+	case "windows":
+		require.Equal(t, []PackageFormat{
+			{Name: "jq", Version: "1.7.1", InstalledBy: pkgmgr.ManagerTypeScoop},
+			{Name: "7zip", Version: "23.01", InstalledBy: pkgmgr.ManagerTypeScoop},
+		}, f.Packages)
+	case "darwin":
+		require.Equal(t, []PackageFormat{
+			{Name: "jq", Version: "1.7.1", InstalledBy: pkgmgr.ManagerTypeBrew},
+		}, f.Packages)
+	case "linux":
+		require.Equal(t, []PackageFormat{
+			{Name: "jq", Version: "1.7.1-3build1", InstalledBy: pkgmgr.ManagerTypeApt},
+		}, f.Packages)
+	}
+}
+
+func TestLoadManifestFileYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	data := `# Shared tools
+schemaVersion: 2
+tools:
+  - name: jq
+    version: 1.7.1
+    platforms:
+      windows: {installedBy: scoop}
+      darwin: {installedBy: brew}
+      linux: {installedBy: apt, version: 1.7.1-3build1}
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	f, err := LoadManifestFile(path)
+
+	require.NoError(t, err)
+	require.Len(t, f.Packages, 1)
+	require.Equal(t, "jq", f.Packages[0].Name)
+}
+
+func TestToolsFromManifestRoundTrip(t *testing.T) {
+	m := &ManifestFile{
+		Platform: "darwin",
+		Packages: []PackageFormat{
+			{Name: "git", Version: "2.44.0", InstalledBy: pkgmgr.ManagerTypeBrew},
+			{Name: "pcre2", Version: "10.42", InstalledBy: pkgmgr.ManagerTypeBrew, Dependency: true},
+		},
+	}
+
+	f := ToolsFromManifest(m)
+
+	require.True(t, f.Tools[1].Platforms["darwin"].Dependency)
+	require.Equal(t, m, f.ForPlatform("darwin"))
+}
+
+func TestToolsFromManifestRoundTripVersionedName(t *testing.T) {
+	m := &ManifestFile{
+		Platform: "darwin",
+		Packages: []PackageFormat{
+			{Name: "openssl@3", Version: "3.2.1", InstalledBy: pkgmgr.ManagerTypeBrew},
+			{Name: "python@3.11", InstalledBy: pkgmgr.ManagerTypeBrew},
+			{Name: "python@3.12", Version: "3.12.1", InstalledBy: pkgmgr.ManagerTypeBrew},
+		},
+	}
+
+	f := ToolsFromManifest(m)
+
+	require.NoError(t, f.Validate())
+	require.Equal(t, "python@3.12@", f.Tools[2].Name)
+	require.Equal(t, m, f.ForPlatform("darwin"))
+
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	require.NoError(t, SaveManifestFile(path, m))
+	loaded, _, err := LoadToolManifestFile(path)
+	require.NoError(t, err)
+	require.Equal(t, m, loaded.ForPlatform("darwin"))
+}
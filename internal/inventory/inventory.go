@@ -0,0 +1,135 @@
+// Package inventory compares the packages tracked in the configuration with
+// the packages actually installed by each package manager.
+package inventory
+
+import (
+	"cmp"
+	"slices"
+
+	"devctl/internal/config"
+	"devctl/pkg/pkgmgr"
+	"devctl/pkg/version"
+)
+
+// Installed holds the packages reported by List for each package manager.
+type Installed map[pkgmgr.ManagerType][]pkgmgr.Package
+
+// Entry describes a single package in a Report.
+type Entry struct {
+	Name        string
+	InstalledBy pkgmgr.ManagerType
+	// Repository is the bucket, tap or repository of the package.
+	// Empty if neither the configuration nor the package manager names one.
+	Repository string
+	// Tracked is the version recorded in the configuration.
+	// Empty for untracked packages.
+	Tracked string
+	// Installed is the version reported by the package manager.
+	// Empty for missing packages.
+	Installed string
+}
+
+// Report groups packages by how the tracked state relates to the installed state.
+type Report struct {
+	// InSync packages are installed with the tracked version.
+	InSync []Entry
+	// Mismatched packages are installed with a different version.
+	Mismatched []Entry
+	// Missing packages are tracked but not installed.
+	Missing []Entry
+	// Untracked packages are installed but not tracked.
+	Untracked []Entry
+}
+
+// HasDrift reports whether any tracked package is missing or has a different version.
+// Untracked packages are not considered drift.
+func (r *Report) HasDrift() bool {
+	return len(r.Mismatched) > 0 || len(r.Missing) > 0
+}
+
+// Compare builds a Report from the tracked packages and the installed packages.
+// Untracked packages are only reported for managers present in installed.
+// Packages are matched by manager, repository and name, see config.SamePackage.
+func Compare(tracked []config.PackageConfig, installed Installed) *Report {
+	report := &Report{}
+
+	for _, pkg := range tracked {
+		entry := Entry{
+			Name:        pkg.Name,
+			InstalledBy: pkg.InstalledBy,
+			Repository:  pkg.Repository,
+			Tracked:     pkg.Version,
+		}
+
+		live := FindFrom(installed[pkg.InstalledBy], pkg.Repository, pkg.Name)
+		if live != nil && entry.Repository == "" {
+			entry.Repository = live.Repository
+		}
+		switch {
+		case live == nil:
+			report.Missing = append(report.Missing, entry)
+		case version.Satisfies(live.Version, pkg.Version):
+			entry.Installed = live.Version
+			report.InSync = append(report.InSync, entry)
+		default:
+			entry.Installed = live.Version
+			report.Mismatched = append(report.Mismatched, entry)
+		}
+	}
+
+	for mgrType, pkgs := range installed {
+		for _, pkg := range pkgs {
+			if IsTracked(tracked, mgrType, pkg) {
+				continue
+			}
+			report.Untracked = append(report.Untracked, Entry{
+				Name:        pkg.Name,
+				InstalledBy: mgrType,
+				Repository:  pkg.Repository,
+				Installed:   pkg.Version,
+			})
+		}
+	}
+
+	for _, group := range [][]Entry{report.InSync, report.Mismatched, report.Missing, report.Untracked} {
+		slices.SortFunc(group, compareEntries)
+	}
+
+	return report
+}
+
+// IsTracked reports whether pkg, installed by mgrType, is one of the tracked packages.
+func IsTracked(tracked []config.PackageConfig, mgrType pkgmgr.ManagerType, pkg pkgmgr.Package) bool {
+	live := config.PackageConfig{Name: pkg.Name, InstalledBy: mgrType, Repository: pkg.Repository}
+	return slices.ContainsFunc(tracked, func(t config.PackageConfig) bool {
+		return config.SamePackage(t, live)
+	})
+}
+
+// Find returns the package with the given name, or nil if it is not in pkgs.
+func Find(pkgs []pkgmgr.Package, name string) *pkgmgr.Package {
+	return FindFrom(pkgs, "", name)
+}
+
+// FindFrom returns the package with the given name from repository, or nil
+// if it is not in pkgs. An empty repository matches any repository, as does
+// a package whose manager does not report one.
+func FindFrom(pkgs []pkgmgr.Package, repository, name string) *pkgmgr.Package {
+	for i := range pkgs {
+		if pkgs[i].Name != name {
+			continue
+		}
+		if repository == "" || pkgs[i].Repository == "" || pkgs[i].Repository == repository {
+			return &pkgs[i]
+		}
+	}
+	return nil
+}
+
+func compareEntries(a, b Entry) int {
+	return cmp.Or(
+		cmp.Compare(a.InstalledBy, b.InstalledBy),
+		cmp.Compare(a.Repository, b.Repository),
+		cmp.Compare(a.Name, b.Name),
+	)
+}
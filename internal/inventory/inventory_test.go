@@ -0,0 +1,85 @@
+package inventory
+
+import (
+	"testing"
+
+	"devctl/internal/config"
+	"devctl/pkg/pkgmgr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	tracked := []config.PackageConfig{
+		{Name: "git", Version: "2.43.0", InstalledBy: pkgmgr.ManagerTypeScoop},
+		{Name: "curl", Version: "8.4.0", InstalledBy: pkgmgr.ManagerTypeScoop},
+		{Name: "jq", Version: "1.7.1", InstalledBy: pkgmgr.ManagerTypeScoop},
+		{Name: "PSReadLine", Version: "2.3.4", InstalledBy: pkgmgr.ManagerTypePwsh},
+	}
+	installed := Installed{
+		pkgmgr.ManagerTypeScoop: {
+			{Name: "git", Version: "v2.43.0"},
+			{Name: "curl", Version: "8.5.0"},
+			{Name: "7zip", Version: "23.01"},
+		},
+	}
+
+	report := Compare(tracked, installed)
+
+	require.Equal(t, []Entry{
+		{Name: "git", InstalledBy: pkgmgr.ManagerTypeScoop, Tracked: "2.43.0", Installed: "v2.43.0"},
+	}, report.InSync)
+	require.Equal(t, []Entry{
+		{Name: "curl", InstalledBy: pkgmgr.ManagerTypeScoop, Tracked: "8.4.0", Installed: "8.5.0"},
+	}, report.Mismatched)
+	require.Equal(t, []Entry{
+		{Name: "PSReadLine", InstalledBy: pkgmgr.ManagerTypePwsh, Tracked: "2.3.4"},
+		{Name: "jq", InstalledBy: pkgmgr.ManagerTypeScoop, Tracked: "1.7.1"},
+	}, report.Missing)
+	require.Equal(t, []Entry{
+		{Name: "7zip", InstalledBy: pkgmgr.ManagerTypeScoop, Installed: "23.01"},
+	}, report.Untracked)
+	require.True(t, report.HasDrift())
+}
+
+func TestCompareNoDrift(t *testing.T) {
+	tracked := []config.PackageConfig{
+		{Name: "git", Version: "2.43.0", InstalledBy: pkgmgr.ManagerTypeBrew},
+	}
+	installed := Installed{
+		pkgmgr.ManagerTypeBrew: {
+			{Name: "git", Version: "2.43.0"},
+			{Name: "wget", Version: "1.21.4"},
+		},
+	}
+
+	report := Compare(tracked, installed)
+
+	require.Len(t, report.InSync, 1)
+	require.Len(t, report.Untracked, 1)
+	require.False(t, report.HasDrift())
+}
+
+func TestCompareRepositories(t *testing.T) {
+	tracked := []config.PackageConfig{
+		{Name: "python", Version: "3.12.2", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "main"},
+		{Name: "git", Version: "2.43.0", InstalledBy: pkgmgr.ManagerTypeScoop},
+	}
+	installed := Installed{
+		pkgmgr.ManagerTypeScoop: {
+			{Name: "git", Version: "2.43.0", Repository: "main"},
+			{Name: "python", Version: "3.11.8", Repository: "versions"},
+			{Name: "python", Version: "3.12.2", Repository: "main"},
+		},
+	}
+
+	report := Compare(tracked, installed)
+
+	require.Equal(t, []Entry{
+		{Name: "git", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "main", Tracked: "2.43.0", Installed: "2.43.0"},
+		{Name: "python", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "main", Tracked: "3.12.2", Installed: "3.12.2"},
+	}, report.InSync)
+	require.Equal(t, []Entry{
+		{Name: "python", InstalledBy: pkgmgr.ManagerTypeScoop, Repository: "versions", Installed: "3.11.8"},
+	}, report.Untracked)
+}
@@ -0,0 +1,187 @@
+// Package journal records the progress of an atomic apply on disk, so that a
+// failed run can be rolled back and a crashed run can be finished or undone
+// by 'devctl recover'.
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"devctl/internal/plan"
+	"devctl/pkg/fileutil"
+)
+
+// FileName is the name of the journal file in the data directory.
+const FileName = "journal.json"
+
+// ErrExists is returned by Create when the journal of an earlier run was not
+// cleaned up, which means that run was interrupted.
+var ErrExists = errors.New("an interrupted run was found, run 'devctl recover' first")
+
+// Path returns the path of the journal file in dataDir.
+func Path(dataDir string) string {
+	return filepath.Join(dataDir, FileName)
+}
+
+// Status is the progress of a single action.
+type Status string
+
+const (
+	// StatusStarted actions may have taken effect partially or not at all.
+	StatusStarted Status = "started"
+	// StatusDone actions took effect.
+	StatusDone Status = "done"
+)
+
+// Step is an action of the plan that was started.
+type Step struct {
+	// Index is the position of the action in Plan.Actions.
+	Index  int    `json:"index"`
+	Status Status `json:"status"`
+}
+
+// Journal is the plan of a run and the steps taken so far, in the order they
+// were started. All methods are safe for concurrent use.
+type Journal struct {
+	StartedAt time.Time  `json:"startedAt"`
+	Plan      *plan.Plan `json:"plan"`
+	Steps     []Step     `json:"steps"`
+
+	mu   sync.Mutex
+	path string
+}
+
+// Create starts a new journal for p at path. It returns ErrExists if there
+// already is a journal at path.
+func Create(path string, p *plan.Plan, now time.Time) (*Journal, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, ErrExists
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to check journal: %w", err)
+	}
+
+	j := &Journal{
+		StartedAt: now.UTC(),
+		Plan:      p,
+		Steps:     []Step{},
+		path:      path,
+	}
+	if err := j.save(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// Load reads the journal at path.
+func Load(path string) (*Journal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	var j Journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("failed to parse journal: %w", err)
+	}
+	if j.Plan == nil {
+		return nil, fmt.Errorf("invalid journal: missing plan")
+	}
+	if err := j.Plan.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid journal: %w", err)
+	}
+	for _, s := range j.Steps {
+		if s.Index < 0 || s.Index >= len(j.Plan.Actions) {
+			return nil, fmt.Errorf("invalid journal: step for unknown action %d", s.Index)
+		}
+	}
+
+	j.path = path
+	return &j, nil
+}
+
+// Start records that the actions at indexes are about to be executed.
+// An action that is started again moves to the end of the steps.
+func (j *Journal) Start(indexes ...int) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.Steps = slices.DeleteFunc(j.Steps, func(s Step) bool {
+		return slices.Contains(indexes, s.Index)
+	})
+	for _, i := range indexes {
+		j.Steps = append(j.Steps, Step{Index: i, Status: StatusStarted})
+	}
+	return j.save()
+}
+
+// Done records that the actions at indexes took effect.
+func (j *Journal) Done(indexes ...int) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i := range j.Steps {
+		if slices.Contains(indexes, j.Steps[i].Index) {
+			j.Steps[i].Status = StatusDone
+		}
+	}
+	return j.save()
+}
+
+// Status returns the status of the action at index, or "" if it was not started.
+func (j *Journal) Status(index int) Status {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, s := range j.Steps {
+		if s.Index == index {
+			return s.Status
+		}
+	}
+	return ""
+}
+
+// Undo returns the indexes of the started actions in the order they have to
+// be undone, that is last started first.
+func (j *Journal) Undo() []int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	indexes := make([]int, 0, len(j.Steps))
+	for i := len(j.Steps) - 1; i >= 0; i-- {
+		indexes = append(indexes, j.Steps[i].Index)
+	}
+	return indexes
+}
+
+// Remove deletes the journal file once the run is finished or undone.
+func (j *Journal) Remove() error {
+	if err := os.Remove(j.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove journal: %w", err)
+	}
+	return nil
+}
+
+// save writes the journal to a temporary file and renames it over the
+// journal, so that a crash never leaves a truncated journal behind.
+// The caller holds mu unless the journal is not shared yet.
+func (j *Journal) save() error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	if err := fileutil.WriteFileAtomic(j.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"devctl/internal/plan"
+	"devctl/pkg/pkgmgr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testPlan() *plan.Plan {
+	return &plan.Plan{
+		Platform: "linux",
+		Actions: []plan.Action{
+			{Type: plan.ActionInstall, Name: "jq", InstalledBy: pkgmgr.ManagerTypeApt, Version: "1.7.1"},
+			{Type: plan.ActionUpgrade, Name: "git", InstalledBy: pkgmgr.ManagerTypeApt, Version: "2.44.0", CurrentVersion: "2.43.0"},
+			{Type: plan.ActionRemove, Name: "old-tool", InstalledBy: pkgmgr.ManagerTypeApt, CurrentVersion: "1.0.0"},
+		},
+	}
+}
+
+func TestJournal(t *testing.T) {
+	path := Path(t.TempDir())
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	j, err := Create(path, testPlan(), now)
+	require.NoError(t, err)
+
+	require.NoError(t, j.Start(0, 2))
+	require.NoError(t, j.Done(0))
+	require.NoError(t, j.Start(1))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, now, loaded.StartedAt)
+	require.Equal(t, testPlan(), loaded.Plan)
+	require.Equal(t, StatusDone, loaded.Status(0))
+	require.Equal(t, StatusStarted, loaded.Status(1))
+	require.Equal(t, StatusStarted, loaded.Status(2))
+	require.Equal(t, []int{1, 2, 0}, loaded.Undo())
+
+	require.NoError(t, loaded.Start(0))
+	require.Equal(t, StatusStarted, loaded.Status(0))
+	require.Equal(t, []int{0, 1, 2}, loaded.Undo())
+
+	require.NoError(t, loaded.Remove())
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCreateExisting(t *testing.T) {
+	path := Path(t.TempDir())
+	_, err := Create(path, testPlan(), time.Now())
+	require.NoError(t, err)
+
+	_, err = Create(path, testPlan(), time.Now())
+
+	require.ErrorIs(t, err, ErrExists)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "not json",
+			content: "{",
+			wantErr: "failed to parse journal",
+		},
+		{
+			name:    "missing plan",
+			content: `{"steps": []}`,
+			wantErr: "missing plan",
+		},
+		{
+			name:    "unknown action",
+			content: `{"plan": {"platform": "linux", "actions": []}, "steps": [{"index": 3, "status": "done"}]}`,
+			wantErr: "step for unknown action 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			_, err := Load(path)
+
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,458 @@
+// Package plan computes the changes needed to bring the installed packages
+// in line with a list of desired packages, and executes them.
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+
+	"devctl/internal/config"
+	"devctl/internal/inventory"
+	"devctl/pkg/pkgmgr"
+	"devctl/pkg/version"
+)
+
+// ActionType is the kind of change an Action makes.
+type ActionType string
+
+const (
+	// ActionInstall installs a package that is not installed.
+	ActionInstall ActionType = "install"
+	// ActionUpgrade replaces an installed package with a newer version.
+	ActionUpgrade ActionType = "upgrade"
+	// ActionDowngrade replaces an installed package with an older version.
+	ActionDowngrade ActionType = "downgrade"
+	// ActionReinstall replaces an installed package with a version that
+	// cannot be ordered against the installed one.
+	ActionReinstall ActionType = "reinstall"
+	// ActionRemove uninstalls a package.
+	ActionRemove ActionType = "remove"
+	// ActionNoop leaves an already satisfied package untouched.
+	ActionNoop ActionType = "no-op"
+)
+
+// Action is a single planned change to a package.
+type Action struct {
+	Type        ActionType         `json:"type"`
+	Name        string             `json:"name"`
+	InstalledBy pkgmgr.ManagerType `json:"installedBy"`
+	// Repository is the bucket, tap or repository of the package.
+	// Empty matches the package from any repository.
+	Repository string `json:"repository,omitempty"`
+	// Version is the desired version constraint. Empty for ActionRemove.
+	Version string `json:"version,omitempty"`
+	// CurrentVersion is the installed version when the plan was made.
+	// Empty if the package was not installed.
+	CurrentVersion string `json:"currentVersion,omitempty"`
+}
+
+// Package returns the package tracked in the configuration after the action succeeds.
+func (a *Action) Package() config.PackageConfig {
+	return config.PackageConfig{
+		Name:        a.Name,
+		Version:     a.Version,
+		InstalledBy: a.InstalledBy,
+		Repository:  a.Repository,
+	}
+}
+
+// Execute performs the action with the given manager.
+func (a *Action) Execute(ctx context.Context, mgr pkgmgr.Manager) error {
+	switch a.Type {
+	case ActionNoop:
+		return nil
+	case ActionInstall:
+		if err := mgr.Install(ctx, a.nameWithVersion()); err != nil {
+			return fmt.Errorf("failed to install: %w", err)
+		}
+	case ActionUpgrade, ActionDowngrade, ActionReinstall:
+		if err := a.replace(ctx, mgr); err != nil {
+			return err
+		}
+		return a.verify(ctx, mgr)
+	case ActionRemove:
+		if err := mgr.Uninstall(ctx, a.Name); err != nil {
+			return fmt.Errorf("failed to uninstall: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown action %q", a.Type)
+	}
+	return nil
+}
+
+// replace moves an installed package to the desired version without leaving
+// it uninstalled when that fails. In order of preference it uses the native
+// upgrade for upgrades to the latest version, the native version switch,
+// an install over the installed version, and finally an uninstall followed by
+// an install that rolls back to the previous version if the install fails.
+func (a *Action) replace(ctx context.Context, mgr pkgmgr.Manager) error {
+	target := a.exactVersion()
+	if a.Type == ActionDowngrade && target == "" {
+		// Package managers only install exact older versions.
+		return fmt.Errorf("cannot downgrade from %s to %q, pin an exact version", a.CurrentVersion, a.Version)
+	}
+
+	if u, ok := mgr.(pkgmgr.Upgrader); ok && target == "" && a.Type == ActionUpgrade {
+		err := u.Upgrade(ctx, a.Name)
+		if !errors.Is(err, pkgmgr.ErrUnsupported) {
+			if err != nil {
+				return fmt.Errorf("failed to upgrade: %w", err)
+			}
+			return nil
+		}
+	}
+
+	if s, ok := mgr.(pkgmgr.VersionSwitcher); ok && target != "" {
+		err := s.SwitchVersion(ctx, a.Name, a.CurrentVersion, target)
+		if !errors.Is(err, pkgmgr.ErrUnsupported) {
+			if err != nil {
+				return fmt.Errorf("failed to switch version: %w", err)
+			}
+			return nil
+		}
+	}
+
+	err := mgr.Install(ctx, a.nameWithVersion())
+	if err == nil {
+		err = a.checkChanged(ctx, mgr)
+	}
+	if !errors.Is(err, pkgmgr.ErrAlreadyInstalled) {
+		if err != nil {
+			return fmt.Errorf("failed to install: %w", err)
+		}
+		return nil
+	}
+
+	// The manager does not install over an installed package.
+	if err := mgr.Uninstall(ctx, a.Name); err != nil {
+		return fmt.Errorf("failed to uninstall: %w", err)
+	}
+	if err := mgr.Install(ctx, a.nameWithVersion()); err != nil {
+		installErr := fmt.Errorf("failed to install: %w", err)
+		if a.CurrentVersion == "" {
+			return installErr
+		}
+		previous := fmt.Sprintf("%s@%s", a.Name, a.CurrentVersion)
+		if rbErr := mgr.Install(context.WithoutCancel(ctx), previous); rbErr != nil {
+			return errors.Join(installErr, fmt.Errorf("failed to roll back to %s: %w", a.CurrentVersion, rbErr))
+		}
+		return fmt.Errorf("%w (rolled back to %s)", installErr, a.CurrentVersion)
+	}
+	return nil
+}
+
+// checkChanged returns pkgmgr.ErrAlreadyInstalled if the installed version
+// is still CurrentVersion, for managers that report success without
+// installing over an installed package.
+func (a *Action) checkChanged(ctx context.Context, mgr pkgmgr.Manager) error {
+	installed, err := mgr.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list installed packages: %w", err)
+	}
+	live := inventory.FindFrom(installed, a.Repository, a.Name)
+	if live != nil && a.CurrentVersion != "" && live.Version == a.CurrentVersion {
+		return pkgmgr.ErrAlreadyInstalled
+	}
+	return nil
+}
+
+// verify returns an error if the installed package does not satisfy the
+// desired version after the action, so that it is not recorded as done.
+func (a *Action) verify(ctx context.Context, mgr pkgmgr.Manager) error {
+	installed, err := mgr.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list installed packages: %w", err)
+	}
+	if a.Satisfied(installed) {
+		return nil
+	}
+	current := "no version"
+	if live := inventory.FindFrom(installed, a.Repository, a.Name); live != nil {
+		current = live.Version
+	}
+	return fmt.Errorf("%s is installed after the %s, which does not satisfy %q", current, a.Type, a.Version)
+}
+
+// Undo restores the package of the action to the state the plan was made
+// against, that is CurrentVersion or not installed. installed are the
+// packages currently installed by mgr. The action may have been executed
+// completely, partially or not at all.
+func (a *Action) Undo(ctx context.Context, mgr pkgmgr.Manager, installed []pkgmgr.Package) error {
+	live := inventory.FindFrom(installed, a.Repository, a.Name)
+	switch {
+	case a.CurrentVersion == "" && live == nil:
+		return nil
+	case a.CurrentVersion == "":
+		if err := mgr.Uninstall(ctx, a.Name); err != nil {
+			return fmt.Errorf("failed to uninstall: %w", err)
+		}
+		return nil
+	case live == nil:
+		if err := mgr.Install(ctx, fmt.Sprintf("%s@%s", a.Name, a.CurrentVersion)); err != nil {
+			return fmt.Errorf("failed to install: %w", err)
+		}
+		return nil
+	case live.Version == a.CurrentVersion:
+		return nil
+	}
+
+	restore := Action{
+		Type:           changeType(live.Version, a.CurrentVersion),
+		Name:           a.Name,
+		InstalledBy:    a.InstalledBy,
+		Repository:     a.Repository,
+		Version:        a.CurrentVersion,
+		CurrentVersion: live.Version,
+	}
+	return restore.Execute(ctx, mgr)
+}
+
+// Satisfied reports whether the installed packages already reflect the
+// outcome of the action.
+func (a *Action) Satisfied(installed []pkgmgr.Package) bool {
+	live := inventory.FindFrom(installed, a.Repository, a.Name)
+	if a.Type == ActionRemove {
+		return live == nil
+	}
+	return live != nil && changeType(live.Version, a.Version) == ActionNoop
+}
+
+// nameWithVersion returns the name to install. Only exact versions are
+// passed to the package manager, other constraints install the latest version.
+// Names that contain "@", like brew's "node@20", always get an "@" so that
+// the version is split off at the last "@".
+func (a *Action) nameWithVersion() string {
+	if exact := a.exactVersion(); exact != "" || strings.Contains(a.Name, "@") {
+		return fmt.Sprintf("%s@%s", a.Name, exact)
+	}
+	return a.Name
+}
+
+// exactVersion returns the desired version if it is an exact version, or "".
+func (a *Action) exactVersion() string {
+	c, err := version.ParseConstraint(a.Version)
+	if err != nil {
+		return ""
+	}
+	exact, _ := c.Exact()
+	return exact
+}
+
+// Plan is an ordered list of actions for a platform.
+type Plan struct {
+	Platform string   `json:"platform"`
+	Actions  []Action `json:"actions"`
+}
+
+// HasChanges reports whether the plan contains any action other than ActionNoop.
+func (p *Plan) HasChanges() bool {
+	for _, a := range p.Actions {
+		if a.Type != ActionNoop {
+			return true
+		}
+	}
+	return false
+}
+
+// ManagerTypes returns the package managers used by the plan in order of first use.
+func (p *Plan) ManagerTypes() []pkgmgr.ManagerType {
+	var types []pkgmgr.ManagerType
+	seen := map[pkgmgr.ManagerType]bool{}
+	for _, a := range p.Actions {
+		if !seen[a.InstalledBy] {
+			seen[a.InstalledBy] = true
+			types = append(types, a.InstalledBy)
+		}
+	}
+	return types
+}
+
+// Batch is a group of actions that are executed with a single call to
+// their package manager.
+type Batch struct {
+	InstalledBy pkgmgr.ManagerType
+	Type        ActionType
+	// Indexes are the positions of the actions in Plan.Actions.
+	Indexes []int
+}
+
+// Batches groups the actions of the plan for execution. Installs and removals
+// are batched per package manager; upgrades, downgrades and reinstalls are
+// executed one at a time. No-op actions are not part of any batch.
+// Batches are ordered by their first action.
+func (p *Plan) Batches() []Batch {
+	var batches []Batch
+	grouped := map[pkgmgr.ManagerType]map[ActionType]int{}
+	for i, a := range p.Actions {
+		switch a.Type {
+		case ActionNoop:
+			continue
+		case ActionInstall, ActionRemove:
+			if grouped[a.InstalledBy] == nil {
+				grouped[a.InstalledBy] = map[ActionType]int{}
+			}
+			if b, ok := grouped[a.InstalledBy][a.Type]; ok {
+				batches[b].Indexes = append(batches[b].Indexes, i)
+				continue
+			}
+			grouped[a.InstalledBy][a.Type] = len(batches)
+		}
+		batches = append(batches, Batch{InstalledBy: a.InstalledBy, Type: a.Type, Indexes: []int{i}})
+	}
+	return batches
+}
+
+// Execute performs the actions of the batch with the given manager.
+func (b *Batch) Execute(ctx context.Context, mgr pkgmgr.Manager, actions []Action) error {
+	if len(b.Indexes) == 1 {
+		a := actions[b.Indexes[0]]
+		return a.Execute(ctx, mgr)
+	}
+
+	names := make([]string, 0, len(b.Indexes))
+	for _, i := range b.Indexes {
+		a := actions[i]
+		if b.Type == ActionRemove {
+			names = append(names, a.Name)
+		} else {
+			names = append(names, a.nameWithVersion())
+		}
+	}
+
+	switch b.Type {
+	case ActionInstall:
+		if err := mgr.Install(ctx, names...); err != nil {
+			return fmt.Errorf("failed to install: %w", err)
+		}
+	case ActionRemove:
+		if err := mgr.Uninstall(ctx, names...); err != nil {
+			return fmt.Errorf("failed to uninstall: %w", err)
+		}
+	default:
+		return fmt.Errorf("cannot batch action %q", b.Type)
+	}
+	return nil
+}
+
+// Validate validates the plan.
+func (p *Plan) Validate() error {
+	if p.Platform == "" {
+		return fmt.Errorf("missing platform")
+	}
+	for i, a := range p.Actions {
+		if a.Name == "" {
+			return fmt.Errorf("action[%d]: package name is required", i)
+		}
+		if a.InstalledBy == "" {
+			return fmt.Errorf("action[%d]: installedBy is required", i)
+		}
+		switch a.Type {
+		case ActionInstall, ActionUpgrade, ActionDowngrade, ActionReinstall, ActionRemove, ActionNoop:
+		default:
+			return fmt.Errorf("action[%d]: unknown action %q", i, a.Type)
+		}
+	}
+	return nil
+}
+
+// CheckCurrent verifies that the installed packages still match the state
+// the plan was made against.
+func (p *Plan) CheckCurrent(installed inventory.Installed) error {
+	if p.Platform != runtime.GOOS {
+		return fmt.Errorf("plan is for platform '%s', but current platform is '%s'", p.Platform, runtime.GOOS)
+	}
+	for _, a := range p.Actions {
+		current := ""
+		if live := inventory.FindFrom(installed[a.InstalledBy], a.Repository, a.Name); live != nil {
+			current = live.Version
+		}
+		if current != a.CurrentVersion {
+			return fmt.Errorf("plan is stale: %s %s was %q when planned, now %q", a.InstalledBy, a.Name, a.CurrentVersion, current)
+		}
+	}
+	return nil
+}
+
+// Options controls how a plan is built.
+type Options struct {
+	// Prune removes tracked packages that are installed but not desired.
+	Prune bool
+	// Tracked are the packages currently tracked in the configuration.
+	// Only tracked packages are ever removed.
+	Tracked []config.PackageConfig
+}
+
+// Build computes the actions needed to go from the installed packages to the desired packages.
+// Actions for desired packages keep their order; removals come last.
+func Build(desired []config.PackageConfig, installed inventory.Installed, opts Options) *Plan {
+	p := &Plan{
+		Platform: runtime.GOOS,
+		Actions:  make([]Action, 0, len(desired)),
+	}
+
+	for _, pkg := range desired {
+		action := Action{
+			Type:        ActionInstall,
+			Name:        pkg.Name,
+			InstalledBy: pkg.InstalledBy,
+			Repository:  pkg.Repository,
+			Version:     pkg.Version,
+		}
+		if live := inventory.FindFrom(installed[pkg.InstalledBy], pkg.Repository, pkg.Name); live != nil {
+			action.CurrentVersion = live.Version
+			action.Type = changeType(live.Version, pkg.Version)
+		}
+		p.Actions = append(p.Actions, action)
+	}
+
+	if opts.Prune {
+		for _, pkg := range opts.Tracked {
+			if slices.ContainsFunc(desired, func(d config.PackageConfig) bool {
+				return config.SamePackage(d, pkg)
+			}) {
+				continue
+			}
+			live := inventory.FindFrom(installed[pkg.InstalledBy], pkg.Repository, pkg.Name)
+			if live == nil {
+				continue
+			}
+			p.Actions = append(p.Actions, Action{
+				Type:           ActionRemove,
+				Name:           pkg.Name,
+				InstalledBy:    pkg.InstalledBy,
+				Repository:     pkg.Repository,
+				CurrentVersion: live.Version,
+			})
+		}
+	}
+
+	return p
+}
+
+// changeType returns the action that takes an installed package from current to desired.
+// desired is a version constraint, an installed version that satisfies it is left alone.
+// Installed versions below a range are upgraded to the latest version, versions
+// above it are downgraded, which only succeeds for exact versions.
+func changeType(current, desired string) ActionType {
+	c, err := version.ParseConstraint(desired)
+	if err != nil {
+		if current == desired {
+			return ActionNoop
+		}
+		return ActionReinstall
+	}
+	if c.Check(current) {
+		return ActionNoop
+	}
+	if exact, ok := c.Exact(); ok && (!version.IsValid(current) || !version.IsValid(exact)) {
+		return ActionReinstall
+	}
+	if c.OnlyOlder(current) {
+		return ActionDowngrade
+	}
+	return ActionUpgrade
+}
@@ -0,0 +1,586 @@
+package plan
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"devctl/internal/config"
+	"devctl/internal/inventory"
+	"devctl/pkg/pkgmgr"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingManager records its calls and keeps the installed versions in
+// memory. Installs without a version install latest.
+type recordingManager struct {
+	calls      []string
+	installErr error
+	// installErrs fails the installs of single names, in order of the calls.
+	installErrs map[string][]error
+	// installed maps package names to their installed versions.
+	installed map[string]string
+	latest    string
+	// keepInstalled makes installs of installed packages succeed without
+	// changing them, like "brew install" of an installed formula.
+	keepInstalled bool
+}
+
+func (m *recordingManager) Install(_ context.Context, names ...string) error {
+	m.calls = append(m.calls, "install "+strings.Join(names, " "))
+	if len(names) == 1 && len(m.installErrs[names[0]]) > 0 {
+		err := m.installErrs[names[0]][0]
+		m.installErrs[names[0]] = m.installErrs[names[0]][1:]
+		return err
+	}
+	if m.installErr != nil {
+		return m.installErr
+	}
+	for _, name := range names {
+		v := m.latestVersion()
+		if i := strings.LastIndex(name, "@"); i > 0 {
+			name, v = name[:i], cmp.Or(name[i+1:], v)
+		}
+		if _, ok := m.installed[name]; ok && m.keepInstalled {
+			continue
+		}
+		m.setInstalled(name, v)
+	}
+	return nil
+}
+
+func (m *recordingManager) Uninstall(_ context.Context, names ...string) error {
+	m.calls = append(m.calls, "uninstall "+strings.Join(names, " "))
+	for _, name := range names {
+		delete(m.installed, name)
+	}
+	return nil
+}
+
+func (m *recordingManager) List(context.Context) ([]pkgmgr.Package, error) {
+	var pkgs []pkgmgr.Package
+	for name, v := range m.installed {
+		pkgs = append(pkgs, pkgmgr.Package{Name: name, Version: v})
+	}
+	return pkgs, nil
+}
+
+func (m *recordingManager) setInstalled(name, v string) {
+	if m.installed == nil {
+		m.installed = map[string]string{}
+	}
+	m.installed[name] = v
+}
+
+func (m *recordingManager) latestVersion() string {
+	return cmp.Or(m.latest, "99.0.0")
+}
+
+// switchingManager is a recordingManager with native upgrades and version switches.
+type switchingManager struct {
+	recordingManager
+	switchErr error
+}
+
+func (m *switchingManager) Upgrade(_ context.Context, names ...string) error {
+	m.calls = append(m.calls, "upgrade "+strings.Join(names, " "))
+	for _, name := range names {
+		m.setInstalled(name, m.latestVersion())
+	}
+	return nil
+}
+
+func (m *switchingManager) UpgradeCommand(names ...string) []string {
+	return append([]string{"upgrade"}, names...)
+}
+
+func (m *switchingManager) SwitchVersion(_ context.Context, name, from, to string) error {
+	m.calls = append(m.calls, "switch "+name+" "+from+" "+to)
+	if m.switchErr == nil {
+		m.setInstalled(name, to)
+	}
+	return m.switchErr
+}
+
+func (m *switchingManager) SwitchVersionCommand(name, from, to string) []string {
+	return []string{"switch", name, from, to}
+}
+
+func TestBuild(t *testing.T) {
+	scoop := pkgmgr.ManagerTypeScoop
+	desired := []config.PackageConfig{
+		{Name: "git", Version: "2.43.0", InstalledBy: scoop},
+		{Name: "curl", Version: "8.5.0", InstalledBy: scoop},
+		{Name: "jq", Version: "1.6", InstalledBy: scoop},
+		{Name: "7zip", Version: "23.01", InstalledBy: scoop},
+		{Name: "go", Version: "1.22.0", InstalledBy: scoop},
+		{Name: "neovim", Version: "", InstalledBy: scoop},
+	}
+	installed := inventory.Installed{
+		scoop: {
+			{Name: "git", Version: "2.43.0"},
+			{Name: "curl", Version: "8.4.0"},
+			{Name: "jq", Version: "1.7.1"},
+			{Name: "7zip", Version: "22.01"},
+			{Name: "neovim", Version: "0.9.5"},
+			{Name: "old-tool", Version: "1.0.0"},
+			{Name: "untracked", Version: "1.0.0"},
+		},
+	}
+	tracked := []config.PackageConfig{
+		{Name: "old-tool", Version: "1.0.0", InstalledBy: scoop},
+		{Name: "gone", Version: "1.0.0", InstalledBy: scoop},
+	}
+
+	p := Build(desired, installed, Options{Prune: true, Tracked: tracked})
+
+	require.Equal(t, []Action{
+		{Type: ActionNoop, Name: "git", InstalledBy: scoop, Version: "2.43.0", CurrentVersion: "2.43.0"},
+		{Type: ActionUpgrade, Name: "curl", InstalledBy: scoop, Version: "8.5.0", CurrentVersion: "8.4.0"},
+		{Type: ActionDowngrade, Name: "jq", InstalledBy: scoop, Version: "1.6", CurrentVersion: "1.7.1"},
+		{Type: ActionUpgrade, Name: "7zip", InstalledBy: scoop, Version: "23.01", CurrentVersion: "22.01"},
+		{Type: ActionInstall, Name: "go", InstalledBy: scoop, Version: "1.22.0"},
+		{Type: ActionNoop, Name: "neovim", InstalledBy: scoop, CurrentVersion: "0.9.5"},
+		{Type: ActionRemove, Name: "old-tool", InstalledBy: scoop, CurrentVersion: "1.0.0"},
+	}, p.Actions)
+	require.True(t, p.HasChanges())
+	require.Equal(t, []pkgmgr.ManagerType{scoop}, p.ManagerTypes())
+}
+
+func TestChangeType(t *testing.T) {
+	tests := []struct {
+		current string
+		desired string
+		want    ActionType
+	}{
+		{current: "1.7.1", desired: "", want: ActionNoop},
+		{current: "1.7.1", desired: "latest", want: ActionNoop},
+		{current: "1.7.1", desired: "1.7.1", want: ActionNoop},
+		{current: "1.7.1", desired: "1.8.0", want: ActionUpgrade},
+		{current: "1.7.1", desired: "1.6", want: ActionDowngrade},
+		{current: "22.01", desired: "23.01", want: ActionUpgrade},
+		{current: "2.43.0.windows.1", desired: "2.43.0.windows.2", want: ActionUpgrade},
+		{current: "8.5.0_1", desired: "8.5.0", want: ActionNoop},
+		{current: "1:2.34-1ubuntu1", desired: "2.33", want: ActionDowngrade},
+		{current: "1:2.32-1ubuntu1", desired: "2.33", want: ActionUpgrade},
+		{current: "1:2.43.0-1ubuntu7", desired: "2.43.0", want: ActionNoop},
+		{current: "1:2.44.0-1ubuntu1", desired: ">=2.40 <3", want: ActionNoop},
+		{current: "nightly", desired: "1.0.0", want: ActionReinstall},
+		{current: "1.7.1", desired: "^1.6", want: ActionNoop},
+		{current: "1.5.0", desired: "^1.6", want: ActionUpgrade},
+		{current: "2.44.0", desired: ">=2.40 <3", want: ActionNoop},
+		{current: "2.39.0", desired: ">=2.40 <3", want: ActionUpgrade},
+		{current: "3.0", desired: "<2", want: ActionDowngrade},
+		{current: "2.1.0", desired: "^1.6", want: ActionDowngrade},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current+" to "+tt.desired, func(t *testing.T) {
+			require.Equal(t, tt.want, changeType(tt.current, tt.desired))
+		})
+	}
+}
+
+func TestBuildWithoutPrune(t *testing.T) {
+	installed := inventory.Installed{
+		pkgmgr.ManagerTypeBrew: {{Name: "wget", Version: "1.21.4"}},
+	}
+	tracked := []config.PackageConfig{
+		{Name: "wget", Version: "1.21.4", InstalledBy: pkgmgr.ManagerTypeBrew},
+	}
+
+	p := Build(nil, installed, Options{Tracked: tracked})
+
+	require.Empty(t, p.Actions)
+	require.False(t, p.HasChanges())
+}
+
+func TestBuildPruneRepositories(t *testing.T) {
+	scoop := pkgmgr.ManagerTypeScoop
+	installed := inventory.Installed{
+		scoop: {
+			{Name: "python", Version: "3.12.2", Repository: "main"},
+			{Name: "python", Version: "3.11.8", Repository: "versions"},
+		},
+		"nvm": {{Name: "node", Version: "20.11.0"}},
+	}
+	desired := []config.PackageConfig{
+		{Name: "python", Version: "3.12.2", InstalledBy: scoop, Repository: "main"},
+	}
+	tracked := []config.PackageConfig{
+		{Name: "python", Version: "3.12.2", InstalledBy: scoop, Repository: "main"},
+		{Name: "python", Version: "3.11.8", InstalledBy: scoop, Repository: "versions"},
+		{Name: "node", Version: "20.11.0", InstalledBy: "nvm"},
+	}
+
+	p := Build(desired, installed, Options{Prune: true, Tracked: tracked})
+
+	require.Equal(t, []Action{
+		{Type: ActionNoop, Name: "python", InstalledBy: scoop, Repository: "main", Version: "3.12.2", CurrentVersion: "3.12.2"},
+		{Type: ActionRemove, Name: "python", InstalledBy: scoop, Repository: "versions", CurrentVersion: "3.11.8"},
+		{Type: ActionRemove, Name: "node", InstalledBy: "nvm", CurrentVersion: "20.11.0"},
+	}, p.Actions)
+}
+
+func TestActionExecute(t *testing.T) {
+	tests := []struct {
+		name   string
+		action Action
+		want   []string
+	}{
+		{
+			name:   "install",
+			action: Action{Type: ActionInstall, Name: "git", Version: "2.43.0"},
+			want:   []string{"install git@2.43.0"},
+		},
+		{
+			name:   "install without version",
+			action: Action{Type: ActionInstall, Name: "git"},
+			want:   []string{"install git"},
+		},
+		{
+			name:   "install range installs latest",
+			action: Action{Type: ActionInstall, Name: "jq", Version: "^1.6"},
+			want:   []string{"install jq"},
+		},
+		{
+			name:   "install latest",
+			action: Action{Type: ActionInstall, Name: "jq", Version: "latest"},
+			want:   []string{"install jq"},
+		},
+		{
+			name:   "install name with @",
+			action: Action{Type: ActionInstall, Name: "node@20", Version: "20.11.1"},
+			want:   []string{"install node@20@20.11.1"},
+		},
+		{
+			name:   "install name with @ without version",
+			action: Action{Type: ActionInstall, Name: "node@20", Version: "^20"},
+			want:   []string{"install node@20@"},
+		},
+		{
+			name:   "upgrade",
+			action: Action{Type: ActionUpgrade, Name: "git", Version: "2.44.0", CurrentVersion: "2.43.0"},
+			want:   []string{"install git@2.44.0"},
+		},
+		{
+			name:   "remove",
+			action: Action{Type: ActionRemove, Name: "git", CurrentVersion: "2.43.0"},
+			want:   []string{"uninstall git"},
+		},
+		{
+			name:   "no-op",
+			action: Action{Type: ActionNoop, Name: "git", Version: "2.43.0", CurrentVersion: "2.43.0"},
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr := &recordingManager{}
+
+			err := tt.action.Execute(context.Background(), mgr)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, mgr.calls)
+		})
+	}
+}
+
+func TestActionExecuteReplace(t *testing.T) {
+	alreadyInstalled := func() map[string][]error {
+		return map[string][]error{"git@2.44.0": {pkgmgr.ErrAlreadyInstalled}}
+	}
+
+	t.Run("reinstalls when the manager does not install over a package", func(t *testing.T) {
+		mgr := &recordingManager{installErrs: alreadyInstalled()}
+		action := Action{Type: ActionUpgrade, Name: "git", Version: "2.44.0", CurrentVersion: "2.43.0"}
+
+		require.NoError(t, action.Execute(context.Background(), mgr))
+		require.Equal(t, []string{"install git@2.44.0", "uninstall git", "install git@2.44.0"}, mgr.calls)
+	})
+
+	t.Run("rolls back when the reinstall fails", func(t *testing.T) {
+		mgr := &recordingManager{installErrs: alreadyInstalled()}
+		mgr.installErrs["git@2.44.0"] = append(mgr.installErrs["git@2.44.0"], errors.New("network down"))
+		action := Action{Type: ActionUpgrade, Name: "git", Version: "2.44.0", CurrentVersion: "2.43.0"}
+
+		err := action.Execute(context.Background(), mgr)
+
+		require.ErrorContains(t, err, "failed to install: network down (rolled back to 2.43.0)")
+		require.Equal(t, []string{"install git@2.44.0", "uninstall git", "install git@2.44.0", "install git@2.43.0"}, mgr.calls)
+	})
+
+	t.Run("reports a failed roll back", func(t *testing.T) {
+		mgr := &recordingManager{installErrs: alreadyInstalled()}
+		mgr.installErrs["git@2.44.0"] = append(mgr.installErrs["git@2.44.0"], errors.New("network down"))
+		mgr.installErrs["git@2.43.0"] = []error{errors.New("still down")}
+		action := Action{Type: ActionUpgrade, Name: "git", Version: "2.44.0", CurrentVersion: "2.43.0"}
+
+		err := action.Execute(context.Background(), mgr)
+
+		require.ErrorContains(t, err, "failed to roll back to 2.43.0: still down")
+	})
+
+	t.Run("reinstalls when installing over leaves the version unchanged", func(t *testing.T) {
+		mgr := &recordingManager{keepInstalled: true, installed: map[string]string{"git": "2.43.0"}}
+		action := Action{Type: ActionUpgrade, Name: "git", Version: "2.44.0", CurrentVersion: "2.43.0"}
+
+		require.NoError(t, action.Execute(context.Background(), mgr))
+		require.Equal(t, []string{"install git@2.44.0", "uninstall git", "install git@2.44.0"}, mgr.calls)
+		require.Equal(t, "2.44.0", mgr.installed["git"])
+	})
+
+	t.Run("keeps the installed version when the install fails", func(t *testing.T) {
+		mgr := &recordingManager{installErr: errors.New("network down")}
+		action := Action{Type: ActionDowngrade, Name: "git", Version: "2.42.0", CurrentVersion: "2.43.0"}
+
+		require.ErrorContains(t, action.Execute(context.Background(), mgr), "failed to install: network down")
+		require.Equal(t, []string{"install git@2.42.0"}, mgr.calls)
+	})
+
+	t.Run("switches versions natively", func(t *testing.T) {
+		mgr := &switchingManager{}
+		action := Action{Type: ActionDowngrade, Name: "git", Version: "2.42.0", CurrentVersion: "2.43.0"}
+
+		require.NoError(t, action.Execute(context.Background(), mgr))
+		require.Equal(t, []string{"switch git 2.43.0 2.42.0"}, mgr.calls)
+	})
+
+	t.Run("falls back when the switch is unsupported", func(t *testing.T) {
+		mgr := &switchingManager{switchErr: pkgmgr.ErrUnsupported}
+		action := Action{Type: ActionDowngrade, Name: "git", Version: "2.42.0", CurrentVersion: "2.43.0"}
+
+		require.NoError(t, action.Execute(context.Background(), mgr))
+		require.Equal(t, []string{"switch git 2.43.0 2.42.0", "install git@2.42.0"}, mgr.calls)
+	})
+
+	t.Run("installs versioned names when the manager cannot switch versions", func(t *testing.T) {
+		mgr := &recordingManager{}
+		action := Action{Type: ActionUpgrade, Name: "node@20", Version: "20.11.1", CurrentVersion: "20.10.0"}
+
+		require.NoError(t, action.Execute(context.Background(), mgr))
+		require.Equal(t, []string{"install node@20@20.11.1"}, mgr.calls)
+	})
+
+	t.Run("rolls back versioned names", func(t *testing.T) {
+		mgr := &recordingManager{installErrs: map[string][]error{
+			"node@20@20.11.1": {pkgmgr.ErrAlreadyInstalled, errors.New("network down")},
+		}}
+		action := Action{Type: ActionUpgrade, Name: "node@20", Version: "20.11.1", CurrentVersion: "20.10.0"}
+
+		require.Error(t, action.Execute(context.Background(), mgr))
+		require.Equal(t, []string{"install node@20@20.11.1", "uninstall node@20", "install node@20@20.11.1", "install node@20@20.10.0"}, mgr.calls)
+	})
+
+	t.Run("upgrades ranges natively", func(t *testing.T) {
+		mgr := &switchingManager{}
+		mgr.latest = "1.7.1"
+		action := Action{Type: ActionUpgrade, Name: "jq", Version: "^1.7", CurrentVersion: "1.6"}
+
+		require.NoError(t, action.Execute(context.Background(), mgr))
+		require.Equal(t, []string{"upgrade jq"}, mgr.calls)
+	})
+
+	t.Run("fails when the upgrade leaves the range unsatisfied", func(t *testing.T) {
+		mgr := &switchingManager{}
+		mgr.latest = "2.0.0"
+		action := Action{Type: ActionUpgrade, Name: "jq", Version: "^1.7", CurrentVersion: "1.6"}
+
+		err := action.Execute(context.Background(), mgr)
+
+		require.ErrorContains(t, err, `2.0.0 is installed after the upgrade, which does not satisfy "^1.7"`)
+	})
+
+	t.Run("refuses to downgrade to a range", func(t *testing.T) {
+		mgr := &switchingManager{}
+		action := Action{Type: ActionDowngrade, Name: "jq", Version: "<2", CurrentVersion: "3.0"}
+
+		err := action.Execute(context.Background(), mgr)
+
+		require.ErrorContains(t, err, `cannot downgrade from 3.0 to "<2", pin an exact version`)
+		require.Empty(t, mgr.calls)
+	})
+}
+
+func TestActionUndo(t *testing.T) {
+	installed := []pkgmgr.Package{
+		{Name: "git", Version: "2.44.0"},
+		{Name: "go", Version: "1.22.0"},
+	}
+
+	tests := []struct {
+		name   string
+		action Action
+		want   []string
+	}{
+		{
+			name:   "installed package is removed",
+			action: Action{Type: ActionInstall, Name: "go", Version: "1.22.0"},
+			want:   []string{"uninstall go"},
+		},
+		{
+			name:   "install that never ran",
+			action: Action{Type: ActionInstall, Name: "jq", Version: "1.7.1"},
+			want:   nil,
+		},
+		{
+			name:   "removed package is installed again",
+			action: Action{Type: ActionRemove, Name: "jq", CurrentVersion: "1.6"},
+			want:   []string{"install jq@1.6"},
+		},
+		{
+			name:   "upgraded package is downgraded",
+			action: Action{Type: ActionUpgrade, Name: "git", Version: "2.44.0", CurrentVersion: "2.43.0"},
+			want:   []string{"install git@2.43.0"},
+		},
+		{
+			name:   "upgrade that never ran",
+			action: Action{Type: ActionUpgrade, Name: "git", Version: "2.45.0", CurrentVersion: "2.44.0"},
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr := &recordingManager{}
+
+			err := tt.action.Undo(context.Background(), mgr, installed)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, mgr.calls)
+		})
+	}
+}
+
+func TestActionExecuteError(t *testing.T) {
+	mgr := &recordingManager{installErr: errors.New("network down")}
+	action := Action{Type: ActionInstall, Name: "git"}
+
+	err := action.Execute(context.Background(), mgr)
+
+	require.ErrorContains(t, err, "failed to install: network down")
+}
+
+func TestBatches(t *testing.T) {
+	scoop, pwsh := pkgmgr.ManagerTypeScoop, pkgmgr.ManagerTypePwsh
+	p := &Plan{Actions: []Action{
+		{Type: ActionInstall, Name: "git", InstalledBy: scoop},
+		{Type: ActionNoop, Name: "curl", InstalledBy: scoop},
+		{Type: ActionInstall, Name: "posh-git", InstalledBy: pwsh},
+		{Type: ActionUpgrade, Name: "jq", InstalledBy: scoop},
+		{Type: ActionInstall, Name: "go", InstalledBy: scoop},
+		{Type: ActionRemove, Name: "old-tool", InstalledBy: scoop},
+		{Type: ActionRemove, Name: "older-tool", InstalledBy: scoop},
+	}}
+
+	require.Equal(t, []Batch{
+		{InstalledBy: scoop, Type: ActionInstall, Indexes: []int{0, 4}},
+		{InstalledBy: pwsh, Type: ActionInstall, Indexes: []int{2}},
+		{InstalledBy: scoop, Type: ActionUpgrade, Indexes: []int{3}},
+		{InstalledBy: scoop, Type: ActionRemove, Indexes: []int{5, 6}},
+	}, p.Batches())
+}
+
+func TestBatchExecute(t *testing.T) {
+	p := &Plan{Actions: []Action{
+		{Type: ActionInstall, Name: "git", Version: "2.43.0"},
+		{Type: ActionInstall, Name: "go"},
+		{Type: ActionUpgrade, Name: "jq", Version: "1.7.1", CurrentVersion: "1.6"},
+		{Type: ActionRemove, Name: "old-tool", CurrentVersion: "1.0.0"},
+		{Type: ActionRemove, Name: "older-tool", CurrentVersion: "0.9.0"},
+	}}
+	mgr := &recordingManager{}
+
+	for _, b := range p.Batches() {
+		require.NoError(t, b.Execute(context.Background(), mgr, p.Actions))
+	}
+
+	require.Equal(t, []string{
+		"install git@2.43.0 go",
+		"install jq@1.7.1",
+		"uninstall old-tool older-tool",
+	}, mgr.calls)
+}
+
+func TestActionSatisfied(t *testing.T) {
+	installed := []pkgmgr.Package{
+		{Name: "git", Version: "2.43.0"},
+		{Name: "go", Version: "1.22.0"},
+	}
+
+	tests := []struct {
+		name   string
+		action Action
+		want   bool
+	}{
+		{
+			name:   "installed at desired version",
+			action: Action{Type: ActionInstall, Name: "git", Version: "2.43.0"},
+			want:   true,
+		},
+		{
+			name:   "installed without desired version",
+			action: Action{Type: ActionInstall, Name: "go"},
+			want:   true,
+		},
+		{
+			name:   "installed at other version",
+			action: Action{Type: ActionUpgrade, Name: "go", Version: "1.23.0", CurrentVersion: "1.22.0"},
+			want:   false,
+		},
+		{
+			name:   "not installed",
+			action: Action{Type: ActionInstall, Name: "jq"},
+			want:   false,
+		},
+		{
+			name:   "removed",
+			action: Action{Type: ActionRemove, Name: "jq"},
+			want:   true,
+		},
+		{
+			name:   "still installed",
+			action: Action{Type: ActionRemove, Name: "git"},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.action.Satisfied(installed))
+		})
+	}
+}
+
+func TestCheckCurrent(t *testing.T) {
+	p := Build([]config.PackageConfig{
+		{Name: "git", Version: "2.44.0", InstalledBy: pkgmgr.ManagerTypeScoop},
+	}, inventory.Installed{
+		pkgmgr.ManagerTypeScoop: {{Name: "git", Version: "2.43.0"}},
+	}, Options{})
+
+	require.NoError(t, p.CheckCurrent(inventory.Installed{
+		pkgmgr.ManagerTypeScoop: {{Name: "git", Version: "2.43.0"}},
+	}))
+	require.ErrorContains(t, p.CheckCurrent(inventory.Installed{
+		pkgmgr.ManagerTypeScoop: {{Name: "git", Version: "2.44.0"}},
+	}), "plan is stale")
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "plan.json")
+	p := Build([]config.PackageConfig{
+		{Name: "git", Version: "2.44.0", InstalledBy: pkgmgr.ManagerTypeScoop},
+	}, inventory.Installed{}, Options{})
+
+	require.NoError(t, Save(path, p))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, p, loaded)
+}
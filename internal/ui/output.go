@@ -6,6 +6,8 @@ import (
 	"os"
 
 	"devctl/pkg/pkgmgr"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 // Output defines the interface for all terminal output operations.
@@ -44,6 +46,15 @@ type Output interface {
 	// Printf prints a formatted message.
 	Printf(format string, args ...any)
 
+	// PrintStatusReport displays the comparison of tracked and installed packages.
+	PrintStatusReport(report StatusReport)
+
+	// PrintPlan displays the actions of a change plan.
+	PrintPlan(actions []PlanAction)
+
+	// PrintOutdated displays tracked packages with newer versions available.
+	PrintOutdated(entries []OutdatedEntry)
+
 	// NewProgressTracker creates a new progress tracker for package operations.
 	NewProgressTracker(packages []PackageInfo) *ProgressTracker
 }
@@ -76,6 +87,41 @@ type PrerequisiteResult struct {
 	Message string
 }
 
+// StatusReport represents tracked packages compared against installed packages.
+type StatusReport struct {
+	InSync     []StatusEntry
+	Mismatched []StatusEntry
+	Missing    []StatusEntry
+	Untracked  []StatusEntry
+}
+
+// StatusEntry represents a single package in a status report.
+type StatusEntry struct {
+	Name      string
+	Manager   string
+	Tracked   string
+	Installed string
+}
+
+// PlanAction represents a single planned change to a package.
+type PlanAction struct {
+	Type    string
+	Name    string
+	Manager string
+	From    string
+	To      string
+}
+
+// OutdatedEntry represents a tracked package with a newer version available.
+type OutdatedEntry struct {
+	Name      string
+	Manager   string
+	Installed string
+	Tracked   string
+	Latest    string
+	Pinned    bool
+}
+
 // TerminalOutput implements Output for terminal display with colors and formatting.
 type TerminalOutput struct {
 	Out    io.Writer
@@ -183,6 +229,94 @@ func (t *TerminalOutput) PrintInstallCommand(cmd string) {
 	fmt.Fprintf(t.Out, "\nCommand to execute:\n  %s\n\n", t.Styles.Info.Render(cmd))
 }
 
+// PrintStatusReport displays the comparison of tracked and installed packages.
+func (t *TerminalOutput) PrintStatusReport(report StatusReport) {
+	groups := []struct {
+		title   string
+		icon    string
+		style   lipgloss.Style
+		entries []StatusEntry
+	}{
+		{"In sync", IconSuccess, t.Styles.Success, report.InSync},
+		{"Version mismatch", IconError, t.Styles.Error, report.Mismatched},
+		{"Missing", IconError, t.Styles.Error, report.Missing},
+		{"Installed but untracked", IconSkipped, t.Styles.Warning, report.Untracked},
+	}
+
+	for _, g := range groups {
+		fmt.Fprintf(t.Out, "\n%s\n", t.Styles.Title.Render(fmt.Sprintf("%s (%d)", g.title, len(g.entries))))
+		fmt.Fprintf(t.Out, "%s\n", Separator(50))
+		for _, e := range g.entries {
+			var detail string
+			switch {
+			case e.Tracked != "" && e.Installed != "" && e.Tracked != e.Installed:
+				detail = fmt.Sprintf("tracked %s, installed %s", e.Tracked, e.Installed)
+			case e.Installed != "":
+				detail = e.Installed
+			default:
+				detail = e.Tracked
+			}
+			fmt.Fprintf(t.Out, "%s %-8s %-30s %s\n", g.style.Render(g.icon), e.Manager, e.Name, detail)
+		}
+	}
+	fmt.Fprintln(t.Out)
+}
+
+// PrintPlan displays the actions of a change plan.
+func (t *TerminalOutput) PrintPlan(actions []PlanAction) {
+	fmt.Fprintf(t.Out, "\n%s\n", t.Styles.Title.Render("Plan"))
+	fmt.Fprintf(t.Out, "%s\n", Separator(50))
+
+	for _, a := range actions {
+		icon, style := IconPending, t.Styles.Pending
+		switch a.Type {
+		case "install":
+			icon, style = "+", t.Styles.Success
+		case "upgrade", "downgrade", "reinstall":
+			icon, style = "~", t.Styles.Warning
+		case "remove":
+			icon, style = "-", t.Styles.Error
+		}
+
+		var versions string
+		switch {
+		case a.From != "" && a.To != "" && a.From != a.To:
+			versions = fmt.Sprintf("%s -> %s", a.From, a.To)
+		case a.To != "":
+			versions = a.To
+		default:
+			versions = a.From
+		}
+
+		fmt.Fprintf(t.Out, "%s %-10s %-8s %-30s %s\n", style.Render(icon), a.Type, a.Manager, a.Name, versions)
+	}
+	fmt.Fprintln(t.Out)
+}
+
+// PrintOutdated displays tracked packages with newer versions available.
+func (t *TerminalOutput) PrintOutdated(entries []OutdatedEntry) {
+	if len(entries) == 0 {
+		t.Success("All tracked packages are up to date")
+		return
+	}
+
+	fmt.Fprintf(t.Out, "\n%s\n", t.Styles.Title.Render(fmt.Sprintf("Outdated (%d)", len(entries))))
+	fmt.Fprintf(t.Out, "%s\n", Separator(50))
+	fmt.Fprintf(t.Out, "%-8s %-30s %-18s %-18s %s\n", "MANAGER", "NAME", "INSTALLED", "TRACKED", "LATEST")
+	for _, e := range entries {
+		name := e.Name
+		if e.Pinned {
+			name += " (pinned)"
+		}
+		tracked := e.Tracked
+		if tracked == "" {
+			tracked = "-"
+		}
+		fmt.Fprintf(t.Out, "%-8s %-30s %-18s %-18s %s\n", e.Manager, name, e.Installed, tracked, t.Styles.Warning.Render(e.Latest))
+	}
+	fmt.Fprintln(t.Out)
+}
+
 // Println prints a plain line.
 func (t *TerminalOutput) Println(msg string) {
 	fmt.Fprintln(t.Out, msg)
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -30,19 +31,24 @@ type PackageProgress struct {
 	Error   error
 }
 
+// ProgressTracker displays the progress of package operations.
+// It is safe for concurrent use.
 type ProgressTracker struct {
-	packages []PackageProgress
-	current  int
-	program  *tea.Program
-	model    *progressModel
-	output   io.Writer
+	mu          sync.Mutex
+	packages    []PackageProgress
+	onInterrupt func()
+	current     int
+	program     *tea.Program
+	model       *progressModel
+	output      io.Writer
 }
 
 type progressModel struct {
-	packages []PackageProgress
-	current  int
-	spinner  spinner.Model
-	quitting bool
+	packages    []PackageProgress
+	current     int
+	spinner     spinner.Model
+	quitting    bool
+	onInterrupt func()
 }
 
 type tickMsg time.Time
@@ -66,6 +72,9 @@ func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		if msg.String() == "ctrl+c" {
 			m.quitting = true
+			if m.onInterrupt != nil {
+				m.onInterrupt()
+			}
 			return m, tea.Quit
 		}
 	case finalMsg:
@@ -159,15 +168,22 @@ func NewProgressTracker(packages []PackageInfo) *ProgressTracker {
 	}
 }
 
+// OnInterrupt sets a function that is called when the user presses Ctrl+C
+// while the tracker is running. It must be called before Start.
+func (pt *ProgressTracker) OnInterrupt(f func()) {
+	pt.onInterrupt = f
+}
+
 func (pt *ProgressTracker) Start() {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
 
 	model := progressModel{
-		packages: pt.packages,
-		current:  pt.current,
-		spinner:  s,
+		packages:    append([]PackageProgress{}, pt.packages...),
+		current:     pt.current,
+		spinner:     s,
+		onInterrupt: pt.onInterrupt,
 	}
 
 	pt.model = &model
@@ -176,6 +192,9 @@ func (pt *ProgressTracker) Start() {
 }
 
 func (pt *ProgressTracker) StartPackage(index int) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
 	if index < 0 || index >= len(pt.packages) {
 		return
 	}
@@ -186,6 +205,9 @@ func (pt *ProgressTracker) StartPackage(index int) {
 }
 
 func (pt *ProgressTracker) CompletePackage(index int, note string) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
 	if index < 0 || index >= len(pt.packages) {
 		return
 	}
@@ -196,6 +218,9 @@ func (pt *ProgressTracker) CompletePackage(index int, note string) {
 }
 
 func (pt *ProgressTracker) FailPackage(index int, err error) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
 	if index < 0 || index >= len(pt.packages) {
 		return
 	}
@@ -206,6 +231,9 @@ func (pt *ProgressTracker) FailPackage(index int, err error) {
 }
 
 func (pt *ProgressTracker) SkipPackage(index int, note string) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
 	if index < 0 || index >= len(pt.packages) {
 		return
 	}
@@ -217,19 +245,26 @@ func (pt *ProgressTracker) SkipPackage(index int, note string) {
 
 func (pt *ProgressTracker) Stop() {
 	if pt.program != nil {
+		pt.mu.Lock()
 		finalPackages := append([]PackageProgress{}, pt.packages...)
+		pt.mu.Unlock()
 		pt.program.Send(finalMsg{packages: finalPackages})
 		pt.program.Wait()
 	}
 }
 
+// updateDisplay sends a snapshot of the packages to the program.
+// The caller must hold pt.mu.
 func (pt *ProgressTracker) updateDisplay() {
 	if pt.program != nil {
-		pt.program.Send(pt.packages)
+		pt.program.Send(append([]PackageProgress{}, pt.packages...))
 	}
 }
 
 func (pt *ProgressTracker) GetSuccessCount() int {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
 	count := 0
 	for _, pkg := range pt.packages {
 		if pkg.Status == StatusSuccess {
@@ -240,6 +275,9 @@ func (pt *ProgressTracker) GetSuccessCount() int {
 }
 
 func (pt *ProgressTracker) GetFailedCount() int {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
 	count := 0
 	for _, pkg := range pt.packages {
 		if pkg.Status == StatusFailed {
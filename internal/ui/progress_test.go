@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressTrackerConcurrentUpdates(t *testing.T) {
+	packages := make([]PackageInfo, 100)
+	for i := range packages {
+		packages[i] = PackageInfo{Name: "pkg"}
+	}
+	tracker := NewProgressTracker(packages)
+
+	var wg sync.WaitGroup
+	for i := range packages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tracker.StartPackage(i)
+			if i%2 == 0 {
+				tracker.CompletePackage(i, "installed")
+			} else {
+				tracker.FailPackage(i, errors.New("failed"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 50, tracker.GetSuccessCount())
+	require.Equal(t, 50, tracker.GetFailedCount())
+}
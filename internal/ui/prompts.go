@@ -81,3 +81,38 @@ func WaitForUserConfirmation(message string) error {
 
 	return form.Run()
 }
+
+// SelectOption is a choice offered by SelectPackages.
+type SelectOption struct {
+	// Key identifies the option in the result.
+	Key string
+	// Label is the text shown to the user.
+	Label string
+}
+
+// SelectPackages asks the user to pick any number of options and returns
+// the keys of the selected options.
+func SelectPackages(title string, options []SelectOption) ([]string, error) {
+	var selected []string
+
+	opts := make([]huh.Option[string], 0, len(options))
+	for _, o := range options {
+		opts = append(opts, huh.NewOption(o.Label, o.Key))
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title(title).
+				Description("Space to toggle, ctrl+a to select all, Enter to confirm.").
+				Options(opts...).
+				Value(&selected),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		return nil, err
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,121 @@
+// Package codec encodes and decodes documents as JSON, YAML or TOML,
+// choosing the format by file extension.
+//
+// Types are mapped with their json, yaml and toml struct tags respectively,
+// so every field of a document type should carry all three. Fields are
+// written in struct order and map keys are sorted, so output is stable.
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the name of an encoding.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{FormatJSON, FormatYAML, FormatTOML}
+
+// Codec encodes and decodes documents in one format.
+type Codec interface {
+	Format() Format
+	// Extension returns the file extension written for the format, including the dot.
+	Extension() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+var (
+	JSON Codec = jsonCodec{}
+	YAML Codec = yamlCodec{}
+	TOML Codec = tomlCodec{}
+)
+
+// ForFormat returns the codec of a format.
+func ForFormat(format Format) (Codec, error) {
+	switch format {
+	case FormatJSON:
+		return JSON, nil
+	case FormatYAML:
+		return YAML, nil
+	case FormatTOML:
+		return TOML, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// ForPath returns the codec for a file based on its extension.
+// Files with a .yaml, .yml or .toml extension use YAML or TOML,
+// all other files use JSON.
+func ForPath(path string) Codec {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return YAML
+	case ".toml":
+		return TOML
+	default:
+		return JSON
+	}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Format() Format    { return FormatJSON }
+func (jsonCodec) Extension() string { return ".json" }
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.MarshalIndent(v, "", "  ")
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+type yamlCodec struct{}
+
+func (yamlCodec) Format() Format    { return FormatYAML }
+func (yamlCodec) Extension() string { return ".yaml" }
+
+func (yamlCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (yamlCodec) Unmarshal(data []byte, v any) error {
+	return yaml.Unmarshal(data, v)
+}
+
+type tomlCodec struct{}
+
+func (tomlCodec) Format() Format    { return FormatTOML }
+func (tomlCodec) Extension() string { return ".toml" }
+
+func (tomlCodec) Marshal(v any) ([]byte, error) {
+	return toml.Marshal(v)
+}
+
+func (tomlCodec) Unmarshal(data []byte, v any) error {
+	return toml.Unmarshal(data, v)
+}
@@ -0,0 +1,97 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type document struct {
+	Name     string            `json:"name" yaml:"name" toml:"name"`
+	Version  string            `json:"version,omitempty" yaml:"version,omitempty" toml:"version,omitempty"`
+	Secret   string            `json:"-" yaml:"-" toml:"-"`
+	Labels   map[string]string `json:"labels,omitempty" yaml:"labels,omitempty" toml:"labels,omitempty"`
+	Children []document        `json:"children,omitempty" yaml:"children,omitempty" toml:"children,omitempty"`
+}
+
+func TestForPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want Format
+	}{
+		{path: "devctl.json", want: FormatJSON},
+		{path: "devctl.yaml", want: FormatYAML},
+		{path: "devctl.yml", want: FormatYAML},
+		{path: "DEVCTL.YML", want: FormatYAML},
+		{path: "devctl.toml", want: FormatTOML},
+		{path: "devctl", want: FormatJSON},
+		{path: "devctl.txt", want: FormatJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			require.Equal(t, tt.want, ForPath(tt.path).Format())
+		})
+	}
+}
+
+func TestForFormat(t *testing.T) {
+	for _, f := range Formats {
+		c, err := ForFormat(f)
+		require.NoError(t, err)
+		require.Equal(t, f, c.Format())
+	}
+
+	_, err := ForFormat("xml")
+	require.ErrorContains(t, err, `unsupported format "xml"`)
+}
+
+func TestRoundTrip(t *testing.T) {
+	doc := document{
+		Name:    "root",
+		Version: "1.20",
+		Secret:  "not written",
+		Labels:  map[string]string{"b": "2", "a": "1"},
+		Children: []document{
+			{Name: "child", Version: "0.1.0"},
+		},
+	}
+
+	for _, f := range Formats {
+		t.Run(string(f), func(t *testing.T) {
+			c, err := ForFormat(f)
+			require.NoError(t, err)
+
+			data, err := c.Marshal(doc)
+			require.NoError(t, err)
+			require.NotContains(t, string(data), "not written")
+
+			again, err := c.Marshal(doc)
+			require.NoError(t, err)
+			require.Equal(t, string(data), string(again))
+
+			var got document
+			require.NoError(t, c.Unmarshal(data, &got))
+			want := doc
+			want.Secret = ""
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func TestMarshalKeepsFieldOrder(t *testing.T) {
+	doc := document{Name: "root", Version: "1.0.0", Labels: map[string]string{"b": "2", "a": "1"}}
+
+	data, err := YAML.Marshal(doc)
+
+	require.NoError(t, err)
+	require.Equal(t, "name: root\nversion: 1.0.0\nlabels:\n  a: \"1\"\n  b: \"2\"\n", string(data))
+}
+
+func TestUnmarshalYAMLKeepsUnquotedVersion(t *testing.T) {
+	var got document
+
+	require.NoError(t, YAML.Unmarshal([]byte("# comment\nname: jq\nversion: 1.20\n"), &got))
+
+	require.Equal(t, "1.20", got.Version)
+}
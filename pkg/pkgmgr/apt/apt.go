@@ -0,0 +1,347 @@
+package apt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"devctl/pkg/pkgmgr"
+)
+
+// queryFormat is the dpkg-query output format used by List.
+// Fields are tab separated: name, version, status, summary.
+const queryFormat = `${Package}\t${Version}\t${db:Status-Status}\t${binary:Summary}\n`
+
+// Config holds configuration for the apt package manager.
+type Config struct {
+	// ExecutablePath is the path to the apt-get executable.
+	// If empty, defaults to "apt-get" (assumes it's in PATH). A path to the
+	// interactive "apt" frontend is mapped to the apt-get next to it, since
+	// apt does not have a stable command line interface.
+	ExecutablePath string
+	// DpkgQueryPath is the path to the dpkg-query executable.
+	// If empty, defaults to "dpkg-query" (assumes it's in PATH).
+	DpkgQueryPath string
+	// AptMarkPath is the path to the apt-mark executable.
+	// If empty, defaults to "apt-mark" (assumes it's in PATH).
+	AptMarkPath string
+	// DisableSudo runs apt-get directly even when not running as root.
+	DisableSudo bool
+}
+
+// ErrPasswordRequired is returned when sudo needs a password to run apt-get.
+// sudo is run non-interactively, since its prompt would interfere with the
+// progress output; cached credentials from 'sudo -v' are used.
+var ErrPasswordRequired = errors.New("sudo requires a password to run apt-get, run 'sudo -v' first")
+
+// Manager implements pkgmgr.Manager for apt/dpkg based distributions.
+type Manager struct {
+	execPath    string
+	queryPath   string
+	markPath    string
+	sudoPath    string
+	execCommand func(ctx context.Context, name string, arg ...string) *exec.Cmd
+}
+
+// New returns a new apt Manager with the given configuration.
+// Install and Uninstall are run through sudo -n when the current user is not
+// root and sudo is available, unless cfg.DisableSudo is set.
+func New(cfg *Config) *Manager {
+	execPath := "apt-get"
+	queryPath := "dpkg-query"
+	markPath := "apt-mark"
+	disableSudo := false
+	if cfg != nil {
+		if cfg.ExecutablePath != "" {
+			execPath = cfg.ExecutablePath
+			if filepath.Base(execPath) == "apt" {
+				execPath = filepath.Join(filepath.Dir(execPath), "apt-get")
+			}
+		}
+		if cfg.DpkgQueryPath != "" {
+			queryPath = cfg.DpkgQueryPath
+		}
+		if cfg.AptMarkPath != "" {
+			markPath = cfg.AptMarkPath
+		}
+		disableSudo = cfg.DisableSudo
+	}
+
+	sudoPath := ""
+	if !disableSudo && os.Geteuid() > 0 {
+		if p, err := exec.LookPath("sudo"); err == nil {
+			sudoPath = p
+		}
+	}
+
+	return &Manager{
+		execPath:    execPath,
+		queryPath:   queryPath,
+		markPath:    markPath,
+		sudoPath:    sudoPath,
+		execCommand: exec.CommandContext,
+	}
+}
+
+// Install installs one or more packages using apt-get install.
+// Names in the form "name@version" are pinned with apt's "name=version" syntax.
+func (m *Manager) Install(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	_, err := m.runAptGet(ctx, m.InstallCommand(names...))
+	return err
+}
+
+// Uninstall removes one or more packages using apt-get remove.
+// It returns pkgmgr.ErrNotInstalled only if none of the packages was
+// installed; the others are removed in the same run.
+func (m *Manager) Uninstall(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	out, err := m.runAptGet(ctx, m.UninstallCommand(names...))
+	if err != nil {
+		return err
+	}
+	// apt-get remove exits successfully for packages that are not installed.
+	if strings.Count(out, "is not installed, so not removed") >= len(names) {
+		return pkgmgr.ErrNotInstalled
+	}
+	return nil
+}
+
+// Upgrade upgrades one or more installed packages using
+// apt-get install --only-upgrade, which never installs new packages.
+func (m *Manager) Upgrade(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	out, err := m.runAptGet(ctx, m.UpgradeCommand(names...))
+	if err != nil {
+		return err
+	}
+	if strings.Contains(out, "is not installed, so not upgraded") {
+		return pkgmgr.ErrNotInstalled
+	}
+	return nil
+}
+
+// SwitchVersion installs version to of name over the installed version using
+// apt-get install name=to. dpkg replaces the package in place, so the old
+// version stays installed if the new one cannot be installed.
+func (m *Manager) SwitchVersion(ctx context.Context, name, from, to string) error {
+	_, err := m.runAptGet(ctx, m.SwitchVersionCommand(name, from, to))
+	return err
+}
+
+// InstallCommand returns the apt-get install command line for names,
+// including sudo when elevation is needed.
+func (m *Manager) InstallCommand(names ...string) []string {
+	args := []string{"install", "-y", "-q"}
+	for _, name := range names {
+		args = append(args, toAptName(name))
+	}
+	return m.commandLine(args)
+}
+
+// UninstallCommand returns the apt-get remove command line for names,
+// including sudo when elevation is needed.
+func (m *Manager) UninstallCommand(names ...string) []string {
+	args := []string{"remove", "-y", "-q"}
+	for _, name := range names {
+		args = append(args, trimVersion(name))
+	}
+	return m.commandLine(args)
+}
+
+// UpgradeCommand returns the apt-get command line that upgrades names,
+// including sudo when elevation is needed.
+func (m *Manager) UpgradeCommand(names ...string) []string {
+	args := []string{"install", "--only-upgrade", "-y", "-q"}
+	for _, name := range names {
+		args = append(args, trimVersion(name))
+	}
+	return m.commandLine(args)
+}
+
+// SwitchVersionCommand returns the apt-get command line that installs version
+// to of name, including sudo when elevation is needed. Downgrades are allowed.
+func (m *Manager) SwitchVersionCommand(name, _, to string) []string {
+	return m.commandLine([]string{"install", "-y", "-q", "--allow-downgrades", name + "=" + to})
+}
+
+func (m *Manager) commandLine(args []string) []string {
+	cmdline := []string{m.execPath}
+	if m.sudoPath != "" {
+		cmdline = []string{m.sudoPath, "-n", m.execPath}
+	}
+	return append(cmdline, args...)
+}
+
+// List returns the installed packages with their exact versions using dpkg-query.
+// Packages apt-mark reports as automatically installed are flagged as
+// dependencies; if apt-mark is not available, no package is flagged.
+func (m *Manager) List(ctx context.Context) ([]pkgmgr.Package, error) {
+	args := []string{"-W", "-f", queryFormat}
+	cmd := m.execCommand(ctx, m.queryPath, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, &pkgmgr.ExecutionError{
+			Cmd:    m.queryPath + " " + strings.Join(args, " "),
+			Stderr: stderr.String(),
+			Err:    err,
+		}
+	}
+
+	auto := m.autoInstalled(ctx)
+
+	var packages []pkgmgr.Package
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "\t", 4)
+		if len(fields) < 3 || fields[2] != "installed" {
+			continue
+		}
+		pkg := pkgmgr.Package{
+			Name:       fields[0],
+			Version:    fields[1],
+			Source:     "apt",
+			Dependency: auto[fields[0]],
+		}
+		if len(fields) == 4 {
+			pkg.Description = fields[3]
+		}
+		packages = append(packages, pkg)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return packages, nil
+}
+
+// autoInstalled returns the packages marked as automatically installed using
+// apt-mark showauto, or nil if apt-mark fails.
+func (m *Manager) autoInstalled(ctx context.Context) map[string]bool {
+	cmd := m.execCommand(ctx, m.markPath, "showauto")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil
+	}
+
+	auto := map[string]bool{}
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		if name := strings.TrimSpace(scanner.Text()); name != "" {
+			auto[name] = true
+		}
+	}
+	return auto
+}
+
+// runAptGet runs an apt-get command line and returns its output.
+// Failures are classified into the pkgmgr sentinel errors where apt's
+// output allows it.
+func (m *Manager) runAptGet(ctx context.Context, cmdline []string) (string, error) {
+	cmd := m.execCommand(ctx, cmdline[0], cmdline[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		errStr := stderr.String()
+		if m.sudoPath != "" && strings.Contains(errStr, "a password is required") {
+			return "", ErrPasswordRequired
+		}
+		if classified := classifyError(errStr); classified != nil {
+			return "", classified
+		}
+		return "", &pkgmgr.ExecutionError{
+			Cmd:    strings.Join(cmdline, " "),
+			Stderr: errStr,
+			Err:    err,
+		}
+	}
+	return stdout.String(), nil
+}
+
+func classifyError(stderr string) error {
+	switch {
+	case strings.Contains(stderr, "Unable to locate package"),
+		strings.Contains(stderr, "has no installation candidate"),
+		strings.Contains(stderr, "was not found"):
+		return pkgmgr.ErrNotFound
+	case strings.Contains(stderr, "is not installed"):
+		return pkgmgr.ErrNotInstalled
+	default:
+		return nil
+	}
+}
+
+// toAptName converts "name@version" into apt's "name=version" form.
+func toAptName(name string) string {
+	if i := strings.LastIndex(name, "@"); i > 0 {
+		if name[i+1:] == "" {
+			return name[:i]
+		}
+		return name[:i] + "=" + name[i+1:]
+	}
+	return name
+}
+
+func trimVersion(name string) string {
+	if i := strings.LastIndex(name, "@"); i > 0 {
+		return name[:i]
+	}
+	return name
+}
+
+// simulatedInstall matches the "Inst" lines printed by a simulated upgrade:
+//
+//	Inst curl [8.5.0-2ubuntu10.1] (8.5.0-2ubuntu10.2 Ubuntu:24.04/noble-updates [amd64])
+var simulatedInstall = regexp.MustCompile(`^Inst (\S+) \[([^\]]+)\] \((\S+)`)
+
+// Outdated returns the packages with a newer version available using a
+// simulated apt-get upgrade. It does not refresh the package lists and
+// does not need root.
+func (m *Manager) Outdated(ctx context.Context) ([]pkgmgr.OutdatedPackage, error) {
+	args := []string{"upgrade", "--simulate", "-q"}
+	cmd := m.execCommand(ctx, m.execPath, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, &pkgmgr.ExecutionError{
+			Cmd:    m.execPath + " " + strings.Join(args, " "),
+			Stderr: stderr.String(),
+			Err:    err,
+		}
+	}
+
+	var packages []pkgmgr.OutdatedPackage
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		match := simulatedInstall.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		packages = append(packages, pkgmgr.OutdatedPackage{
+			Name:    match[1],
+			Version: match[2],
+			Latest:  match[3],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return packages, nil
+}
@@ -0,0 +1,384 @@
+package apt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"devctl/pkg/pkgmgr"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHelperProcess isn't a real test. It's used to mock exec.Command.
+func TestHelperProcess(_ *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	args := os.Args
+	for i := range args {
+		if args[i] == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+
+	if len(args) > 1 && args[0] == "/usr/bin/sudo" && args[1] == "-n" {
+		if args[len(args)-1] == "needs-password" {
+			_, _ = fmt.Fprintf(os.Stderr, "sudo: a password is required\n")
+			os.Exit(1)
+		}
+		args = args[2:]
+	}
+
+	if len(args) == 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "No command\n")
+		os.Exit(2)
+	}
+
+	cmd, subcmd := args[0], ""
+	if len(args) > 1 {
+		subcmd = args[1]
+	}
+
+	switch cmd {
+	case "apt-get", "/usr/bin/apt-get":
+		pkg := args[len(args)-1]
+		switch subcmd {
+		case "install":
+			switch pkg {
+			case "no-such-package":
+				_, _ = fmt.Fprintf(os.Stderr, "E: Unable to locate package no-such-package\n")
+				os.Exit(100)
+			case "curl=0.0.1":
+				_, _ = fmt.Fprintf(os.Stderr, "E: Version '0.0.1' for 'curl' was not found\n")
+				os.Exit(100)
+			case "not-installed":
+				fmt.Printf("Package not-installed is not installed, so not upgraded.\n")
+				return
+			}
+			fmt.Printf("Setting up %s ...\n", pkg)
+		case "remove":
+			for _, pkg := range args[4:] {
+				if pkg == "not-installed" {
+					fmt.Printf("Package 'not-installed' is not installed, so not removed\n")
+					continue
+				}
+				fmt.Printf("Removing %s ...\n", pkg)
+			}
+		case "upgrade":
+			fmt.Print("Reading package lists...\n")
+			fmt.Print("The following packages will be upgraded:\n  curl libcurl4t64\n")
+			fmt.Print("Inst curl [8.5.0-2ubuntu10.1] (8.5.0-2ubuntu10.2 Ubuntu:24.04/noble-updates [amd64])\n")
+			fmt.Print("Inst libcurl4t64 [8.5.0-2ubuntu10.1] (8.5.0-2ubuntu10.2 Ubuntu:24.04/noble-updates [amd64])\n")
+			fmt.Print("Conf curl (8.5.0-2ubuntu10.2 Ubuntu:24.04/noble-updates [amd64])\n")
+		default:
+			_, _ = fmt.Fprintf(os.Stderr, "E: Invalid operation %s\n", subcmd)
+			os.Exit(100)
+		}
+	case "dpkg-query":
+		fmt.Print("curl\t8.5.0-2ubuntu10.1\tinstalled\tcommand line tool for transferring data with URL syntax\n")
+		fmt.Print("git\t1:2.43.0-1ubuntu7\tinstalled\tfast, scalable, distributed revision control system\n")
+		fmt.Print("vim\t2:9.1.0016-1ubuntu7\tconfig-files\tVi IMproved - enhanced vi editor\n")
+	case "apt-mark":
+		fmt.Print("curl\nlibcurl4t64\n")
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "Unknown command %s\n", cmd)
+		os.Exit(1)
+	}
+}
+
+// fakeExecCommand is a helper to mock exec.Command
+func fakeExecCommand(ctx context.Context, name string, arg ...string) *exec.Cmd {
+	cs := []string{"-test.run=TestHelperProcess", "--", name}
+	cs = append(cs, arg...)
+	cmd := exec.CommandContext(ctx, os.Args[0], cs...)
+	cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1")
+	return cmd
+}
+
+func TestAptInstall(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "apt-get",
+		queryPath:   "dpkg-query",
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		pkgs    []string
+		wantErr error
+	}{
+		{
+			name:    "successful install",
+			pkgs:    []string{"jq"},
+			wantErr: nil,
+		},
+		{
+			name:    "pinned version",
+			pkgs:    []string{"curl@8.5.0-2ubuntu10.1"},
+			wantErr: nil,
+		},
+		{
+			name:    "unknown package",
+			pkgs:    []string{"no-such-package"},
+			wantErr: pkgmgr.ErrNotFound,
+		},
+		{
+			name:    "unknown version",
+			pkgs:    []string{"curl@0.0.1"},
+			wantErr: pkgmgr.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mgr.Install(ctx, tt.pkgs...)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAptUninstall(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "apt-get",
+		queryPath:   "dpkg-query",
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		pkgs    []string
+		wantErr error
+	}{
+		{
+			name:    "successful uninstall",
+			pkgs:    []string{"jq"},
+			wantErr: nil,
+		},
+		{
+			name:    "not installed",
+			pkgs:    []string{"not-installed"},
+			wantErr: pkgmgr.ErrNotInstalled,
+		},
+		{
+			name:    "batch with a package that is not installed",
+			pkgs:    []string{"jq", "not-installed"},
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mgr.Uninstall(ctx, tt.pkgs...)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAptUpgrade(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "apt-get",
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	require.NoError(t, mgr.Upgrade(ctx, "curl"))
+	require.ErrorIs(t, mgr.Upgrade(ctx, "not-installed"), pkgmgr.ErrNotInstalled)
+	require.Equal(t, []string{"apt-get", "install", "--only-upgrade", "-y", "-q", "curl"}, mgr.UpgradeCommand("curl@8.5.0"))
+}
+
+func TestAptSwitchVersion(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "apt-get",
+		execCommand: fakeExecCommand,
+	}
+
+	require.NoError(t, mgr.SwitchVersion(context.Background(), "curl", "8.5.0-2ubuntu10.2", "8.5.0-2ubuntu10.1"))
+	require.Equal(t,
+		[]string{"apt-get", "install", "-y", "-q", "--allow-downgrades", "curl=8.5.0-2ubuntu10.1"},
+		mgr.SwitchVersionCommand("curl", "8.5.0-2ubuntu10.2", "8.5.0-2ubuntu10.1"))
+}
+
+func TestAptInstallWithSudo(t *testing.T) {
+	var gotName string
+	var gotArgs []string
+	mgr := &Manager{
+		execPath:  "/usr/bin/apt-get",
+		queryPath: "dpkg-query",
+		sudoPath:  "/usr/bin/sudo",
+		execCommand: func(ctx context.Context, name string, arg ...string) *exec.Cmd {
+			gotName, gotArgs = name, arg
+			return fakeExecCommand(ctx, name, arg...)
+		},
+	}
+
+	err := mgr.Install(context.Background(), "git@1:2.43.0-1ubuntu7")
+
+	require.NoError(t, err)
+	require.Equal(t, "/usr/bin/sudo", gotName)
+	require.Equal(t, []string{"-n", "/usr/bin/apt-get", "install", "-y", "-q", "git=1:2.43.0-1ubuntu7"}, gotArgs)
+}
+
+func TestAptInstallWithSudoPassword(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "/usr/bin/apt-get",
+		queryPath:   "dpkg-query",
+		sudoPath:    "/usr/bin/sudo",
+		execCommand: fakeExecCommand,
+	}
+
+	err := mgr.Install(context.Background(), "needs-password")
+
+	require.ErrorIs(t, err, ErrPasswordRequired)
+}
+
+func TestAptList(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "apt-get",
+		queryPath:   "dpkg-query",
+		markPath:    "apt-mark",
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	pkgs, err := mgr.List(ctx)
+
+	require.NoError(t, err)
+	require.Len(t, pkgs, 2)
+	require.Equal(t, "curl", pkgs[0].Name)
+	require.Equal(t, "8.5.0-2ubuntu10.1", pkgs[0].Version)
+	require.Equal(t, "apt", pkgs[0].Source)
+	require.True(t, pkgs[0].Dependency)
+	require.Equal(t, "git", pkgs[1].Name)
+	require.Equal(t, "1:2.43.0-1ubuntu7", pkgs[1].Version)
+	require.False(t, pkgs[1].Dependency)
+}
+
+func TestAptOutdated(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "apt-get",
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	pkgs, err := mgr.Outdated(ctx)
+
+	require.NoError(t, err)
+	require.Equal(t, []pkgmgr.OutdatedPackage{
+		{Name: "curl", Version: "8.5.0-2ubuntu10.1", Latest: "8.5.0-2ubuntu10.2"},
+		{Name: "libcurl4t64", Version: "8.5.0-2ubuntu10.1", Latest: "8.5.0-2ubuntu10.2"},
+	}, pkgs)
+}
+
+func TestFakeExecutablesOnPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not executable on windows")
+	}
+
+	dir := t.TempDir()
+	writeScript := func(name, body string) {
+		script := "#!/bin/sh\n" + body + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(script), 0755))
+	}
+	writeScript("apt-get", `echo "$@" > "$0.args"`)
+	writeScript("dpkg-query", `printf 'jq\t1.7.1-3build1\tinstalled\tlightweight JSON processor\n'`)
+	t.Setenv("PATH", dir)
+
+	mgr := New(&Config{DisableSudo: true})
+	ctx := context.Background()
+
+	pkgs, err := mgr.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []pkgmgr.Package{{
+		Name:        "jq",
+		Version:     "1.7.1-3build1",
+		Description: "lightweight JSON processor",
+		Source:      "apt",
+	}}, pkgs)
+
+	require.NoError(t, mgr.Install(ctx, "jq@1.7.1-3build1"))
+	data, err := os.ReadFile(filepath.Join(dir, "apt-get.args"))
+	require.NoError(t, err)
+	require.Equal(t, "install -y -q jq=1.7.1-3build1", strings.TrimSpace(string(data)))
+}
+
+func TestNewWithConfig(t *testing.T) {
+	tests := []struct {
+		name             string
+		cfg              *Config
+		expectedExecPath string
+	}{
+		{
+			name:             "nil config uses default",
+			cfg:              nil,
+			expectedExecPath: "apt-get",
+		},
+		{
+			name:             "empty config uses default",
+			cfg:              &Config{},
+			expectedExecPath: "apt-get",
+		},
+		{
+			name: "apt frontend is mapped to apt-get",
+			cfg: &Config{
+				ExecutablePath: filepath.FromSlash("/usr/bin/apt"),
+			},
+			expectedExecPath: filepath.FromSlash("/usr/bin/apt-get"),
+		},
+		{
+			name: "custom apt-get path",
+			cfg: &Config{
+				ExecutablePath: "/opt/bin/apt-get",
+			},
+			expectedExecPath: "/opt/bin/apt-get",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr := New(tt.cfg)
+
+			require.NotNil(t, mgr)
+			require.Equal(t, tt.expectedExecPath, mgr.execPath)
+			require.Equal(t, "dpkg-query", mgr.queryPath)
+			require.NotNil(t, mgr.execCommand)
+		})
+	}
+}
+
+func TestRegisteredDisableSudo(t *testing.T) {
+	mgr, err := pkgmgr.NewManager(pkgmgr.ManagerTypeApt, pkgmgr.ManagerConfig{ExecutablePath: "apt-get", DisableSudo: true})
+	require.NoError(t, err)
+
+	require.Empty(t, mgr.(*Manager).sudoPath)
+}
+
+func TestInstallGuide(t *testing.T) {
+	d, ok := pkgmgr.Lookup(pkgmgr.ManagerTypeApt)
+	require.True(t, ok)
+	require.NotNil(t, d.InstallGuide)
+
+	guide := d.InstallGuide(pkgmgr.PlatformLinux)
+	require.Equal(t, pkgmgr.ManagerTypeApt, guide.ManagerType)
+	require.NotEmpty(t, guide.Instructions)
+}
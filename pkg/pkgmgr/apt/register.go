@@ -0,0 +1,28 @@
+package apt
+
+import "devctl/pkg/pkgmgr"
+
+func init() {
+	pkgmgr.Register(pkgmgr.Descriptor{
+		Type:      pkgmgr.ManagerTypeApt,
+		Platforms: []pkgmgr.Platform{pkgmgr.PlatformLinux},
+		New: func(cfg pkgmgr.ManagerConfig) (pkgmgr.Manager, error) {
+			return New(&Config{ExecutablePath: cfg.ExecutablePath, DisableSudo: cfg.DisableSudo}), nil
+		},
+		InstallGuide: installGuide,
+	})
+}
+
+func installGuide(p pkgmgr.Platform) *pkgmgr.InstallGuide {
+	return &pkgmgr.InstallGuide{
+		ManagerType: pkgmgr.ManagerTypeApt,
+		Platform:    string(p),
+		URL:         "https://wiki.debian.org/Apt",
+		VerifyCmd:   "apt-get --version",
+		Instructions: []string{
+			"apt comes with Debian, Ubuntu and the distributions based on them and cannot be installed separately",
+			"On other distributions, use Homebrew instead",
+			"To run apt-get without sudo, set disableSudo in the apt package manager configuration",
+		},
+	}
+}
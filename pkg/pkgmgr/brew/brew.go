@@ -0,0 +1,296 @@
+package brew
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"devctl/pkg/pkgmgr"
+	"devctl/pkg/version"
+)
+
+// Config holds configuration for the Homebrew package manager.
+type Config struct {
+	// ExecutablePath is the path to the brew executable.
+	// If empty, defaults to "brew" (assumes it's in PATH).
+	ExecutablePath string
+}
+
+// Manager implements pkgmgr.Manager for the Homebrew package manager.
+//
+// It does not implement pkgmgr.VersionSwitcher: brew keeps a single version
+// per formula and cannot install an older one. Versioned formulae such as
+// "node@20" are separate packages that are tracked under their own name, so
+// moving from "node@20" to "node@22" is a removal and an install rather than
+// a version switch. Version changes fall back to Install, which fails unless
+// brew installs the requested version.
+type Manager struct {
+	execPath    string
+	execCommand func(ctx context.Context, name string, arg ...string) *exec.Cmd
+}
+
+// New returns a new Homebrew Manager with the given configuration.
+// If cfg is nil or ExecutablePath is empty, defaults to "brew".
+func New(cfg *Config) *Manager {
+	execPath := "brew"
+	if cfg != nil && cfg.ExecutablePath != "" {
+		execPath = cfg.ExecutablePath
+	}
+	return &Manager{
+		execPath:    execPath,
+		execCommand: exec.CommandContext,
+	}
+}
+
+// Install installs one or more formulae or casks using brew install.
+// Brew only installs the current version of a formula, so a version given as
+// "name@version" is checked after the install instead of passed to brew.
+// Versioned formulae such as "node@20" are separate packages and are
+// requested as "node@20@" or "node@20@20.11.1".
+func (m *Manager) Install(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	cmdline := m.InstallCommand(names...)
+	cmd := m.execCommand(ctx, cmdline[0], cmdline[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		errStr := stderr.String()
+		switch {
+		case strings.Contains(errStr, "is already installed"):
+			return pkgmgr.ErrAlreadyInstalled
+		case strings.Contains(errStr, "No available formula"),
+			strings.Contains(errStr, "No formulae or casks found"):
+			return pkgmgr.ErrNotFound
+		}
+		return &pkgmgr.ExecutionError{
+			Cmd:    strings.Join(cmdline, " "),
+			Stderr: errStr,
+			Err:    err,
+		}
+	}
+	return m.checkVersions(ctx, names)
+}
+
+// checkVersions returns an error if a version requested as "name@version"
+// is not the installed version of name.
+func (m *Manager) checkVersions(ctx context.Context, names []string) error {
+	wanted := map[string]string{}
+	for _, name := range names {
+		if formula, v := splitVersion(name); v != "" {
+			wanted[formula] = v
+		}
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	installed, err := m.List(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, pkg := range installed {
+		if v, ok := wanted[pkg.Name]; ok && !version.Match(pkg.Version, v) {
+			errs = append(errs, fmt.Errorf("brew installed %s %s instead of %s, brew cannot install older versions of a formula", pkg.Name, pkg.Version, v))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Uninstall uninstalls one or more formulae or casks using brew uninstall.
+func (m *Manager) Uninstall(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	cmdline := m.UninstallCommand(names...)
+	cmd := m.execCommand(ctx, cmdline[0], cmdline[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		errStr := stderr.String()
+		if strings.Contains(errStr, "No such keg") || strings.Contains(errStr, "is not installed") {
+			return pkgmgr.ErrNotInstalled
+		}
+		return &pkgmgr.ExecutionError{
+			Cmd:    strings.Join(cmdline, " "),
+			Stderr: errStr,
+			Err:    err,
+		}
+	}
+	return nil
+}
+
+// Upgrade upgrades one or more installed formulae or casks using brew upgrade.
+func (m *Manager) Upgrade(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	cmdline := m.UpgradeCommand(names...)
+	cmd := m.execCommand(ctx, cmdline[0], cmdline[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		errStr := stderr.String()
+		if strings.Contains(errStr, "not installed") {
+			return pkgmgr.ErrNotInstalled
+		}
+		return &pkgmgr.ExecutionError{
+			Cmd:    strings.Join(cmdline, " "),
+			Stderr: errStr,
+			Err:    err,
+		}
+	}
+	return nil
+}
+
+// InstallCommand returns the brew install command line for names.
+func (m *Manager) InstallCommand(names ...string) []string {
+	cmdline := []string{m.execPath, "install"}
+	for _, name := range names {
+		formula, _ := splitVersion(name)
+		cmdline = append(cmdline, formula)
+	}
+	return cmdline
+}
+
+// UninstallCommand returns the brew uninstall command line for names.
+func (m *Manager) UninstallCommand(names ...string) []string {
+	return append([]string{m.execPath, "uninstall"}, names...)
+}
+
+// UpgradeCommand returns the brew upgrade command line for names.
+func (m *Manager) UpgradeCommand(names ...string) []string {
+	return append([]string{m.execPath, "upgrade"}, names...)
+}
+
+type infoOutput struct {
+	Formulae []struct {
+		Name      string `json:"name"`
+		Desc      string `json:"desc"`
+		Tap       string `json:"tap"`
+		Installed []struct {
+			Version               string `json:"version"`
+			InstalledAsDependency bool   `json:"installed_as_dependency"`
+			InstalledOnRequest    bool   `json:"installed_on_request"`
+		} `json:"installed"`
+	} `json:"formulae"`
+	Casks []struct {
+		Token     string `json:"token"`
+		Desc      string `json:"desc"`
+		Tap       string `json:"tap"`
+		Installed string `json:"installed"`
+	} `json:"casks"`
+}
+
+// List returns the installed formulae and casks using brew info --json=v2 --installed.
+// Formulae that were only installed as a dependency are flagged as such.
+func (m *Manager) List(ctx context.Context) ([]pkgmgr.Package, error) {
+	args := []string{"info", "--json=v2", "--installed"}
+	cmd := m.execCommand(ctx, m.execPath, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, &pkgmgr.ExecutionError{
+			Cmd:    m.execPath + " " + strings.Join(args, " "),
+			Stderr: stderr.String(),
+			Err:    err,
+		}
+	}
+
+	var output infoOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, err
+	}
+
+	packages := make([]pkgmgr.Package, 0, len(output.Formulae)+len(output.Casks))
+	for _, f := range output.Formulae {
+		if len(f.Installed) == 0 {
+			continue
+		}
+		keg := f.Installed[len(f.Installed)-1]
+		packages = append(packages, pkgmgr.Package{
+			Name:        f.Name,
+			Version:     keg.Version,
+			Description: f.Desc,
+			Source:      "brew",
+			Repository:  f.Tap,
+			Dependency:  keg.InstalledAsDependency && !keg.InstalledOnRequest,
+		})
+	}
+	for _, c := range output.Casks {
+		if c.Installed == "" {
+			continue
+		}
+		packages = append(packages, pkgmgr.Package{
+			Name:        c.Token,
+			Version:     c.Installed,
+			Description: c.Desc,
+			Source:      "brew",
+			Repository:  c.Tap,
+		})
+	}
+
+	return packages, nil
+}
+
+type outdatedOutput struct {
+	Formulae []outdatedEntry `json:"formulae"`
+	Casks    []outdatedEntry `json:"casks"`
+}
+
+type outdatedEntry struct {
+	Name              string   `json:"name"`
+	InstalledVersions []string `json:"installed_versions"`
+	CurrentVersion    string   `json:"current_version"`
+}
+
+// Outdated returns the formulae and casks with a newer version available
+// using brew outdated --json=v2.
+func (m *Manager) Outdated(ctx context.Context) ([]pkgmgr.OutdatedPackage, error) {
+	args := []string{"outdated", "--json=v2"}
+	cmd := m.execCommand(ctx, m.execPath, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, &pkgmgr.ExecutionError{
+			Cmd:    m.execPath + " " + strings.Join(args, " "),
+			Stderr: stderr.String(),
+			Err:    err,
+		}
+	}
+
+	var output outdatedOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, err
+	}
+
+	packages := make([]pkgmgr.OutdatedPackage, 0, len(output.Formulae)+len(output.Casks))
+	for _, e := range append(output.Formulae, output.Casks...) {
+		pkg := pkgmgr.OutdatedPackage{
+			Name:   e.Name,
+			Latest: e.CurrentVersion,
+		}
+		if len(e.InstalledVersions) > 0 {
+			pkg.Version = e.InstalledVersions[len(e.InstalledVersions)-1]
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+// splitVersion splits "name@version" at the last "@". Names without "@" have
+// no version.
+func splitVersion(name string) (string, string) {
+	if i := strings.LastIndex(name, "@"); i > 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
@@ -0,0 +1,286 @@
+package brew
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"devctl/pkg/pkgmgr"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHelperProcess isn't a real test. It's used to mock exec.Command.
+func TestHelperProcess(_ *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	args := os.Args
+	for i := range args {
+		if args[i] == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+
+	if len(args) == 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "No command\n")
+		os.Exit(2)
+	}
+
+	cmd, subcmd := args[0], ""
+	if len(args) > 1 {
+		subcmd = args[1]
+	}
+
+	switch cmd {
+	case "brew", "custom-brew", "/opt/homebrew/bin/brew":
+		switch subcmd {
+		case "install":
+			pkg := args[len(args)-1]
+			switch pkg {
+			case "already-installed":
+				_, _ = fmt.Fprintf(os.Stderr, "Error: already-installed 1.0.0 is already installed\n")
+				os.Exit(1)
+			case "no-such-formula":
+				_, _ = fmt.Fprintf(os.Stderr, "Error: No available formula with the name \"no-such-formula\".\n")
+				os.Exit(1)
+			}
+			fmt.Printf("==> Pouring %s\n", pkg)
+		case "uninstall":
+			pkg := args[len(args)-1]
+			if pkg == "not-installed" {
+				_, _ = fmt.Fprintf(os.Stderr, "Error: No such keg: /opt/homebrew/Cellar/not-installed\n")
+				os.Exit(1)
+			}
+			fmt.Printf("Uninstalling %s...\n", pkg)
+		case "info":
+			fmt.Println(`{
+  "formulae": [
+    {"name": "git", "desc": "Distributed revision control system", "tap": "homebrew/core", "installed": [{"version": "2.43.0", "installed_as_dependency": false, "installed_on_request": true}]},
+    {"name": "pcre2", "desc": "Perl compatible regular expressions library", "tap": "homebrew/core", "installed": [{"version": "10.42", "installed_as_dependency": true, "installed_on_request": false}]},
+    {"name": "stale", "desc": "", "installed": []}
+  ],
+  "casks": [
+    {"token": "iterm2", "desc": "Terminal emulator", "tap": "homebrew/cask", "installed": "3.4.23"}
+  ]
+}`)
+		case "outdated":
+			fmt.Println(`{
+  "formulae": [
+    {"name": "git", "installed_versions": ["2.42.0", "2.43.0"], "current_version": "2.44.0", "pinned": false, "pinned_version": null}
+  ],
+  "casks": [
+    {"name": "iterm2", "installed_versions": ["3.4.23"], "current_version": "3.5.0"}
+  ]
+}`)
+		default:
+			_, _ = fmt.Fprintf(os.Stderr, "Unknown brew subcommand %s\n", subcmd)
+			os.Exit(1)
+		}
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "Unknown command %s\n", cmd)
+		os.Exit(1)
+	}
+}
+
+// fakeExecCommand is a helper to mock exec.Command
+func fakeExecCommand(ctx context.Context, name string, arg ...string) *exec.Cmd {
+	cs := []string{"-test.run=TestHelperProcess", "--", name}
+	cs = append(cs, arg...)
+	cmd := exec.CommandContext(ctx, os.Args[0], cs...)
+	cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1")
+	return cmd
+}
+
+func TestBrewInstall(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "brew",
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		pkgs    []string
+		wantErr error
+	}{
+		{
+			name:    "successful install",
+			pkgs:    []string{"jq"},
+			wantErr: nil,
+		},
+		{
+			name:    "already installed",
+			pkgs:    []string{"already-installed"},
+			wantErr: pkgmgr.ErrAlreadyInstalled,
+		},
+		{
+			name:    "unknown formula",
+			pkgs:    []string{"no-such-formula"},
+			wantErr: pkgmgr.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mgr.Install(ctx, tt.pkgs...)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBrewInstallPinned(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "brew",
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	require.Equal(t, []string{"brew", "install", "git", "node@20", "python@3.12"},
+		mgr.InstallCommand("git@2.43.0", "node@20@", "python@3.12@3.12.1"))
+	require.NoError(t, mgr.Install(ctx, "git@2.43.0"))
+	require.NoError(t, mgr.Install(ctx, "git@2.43.0_1"))
+	require.ErrorContains(t, mgr.Install(ctx, "git@2.40.0"), "brew installed git 2.43.0 instead of 2.40.0")
+}
+
+func TestBrewUninstall(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "brew",
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		pkgs    []string
+		wantErr error
+	}{
+		{
+			name:    "successful uninstall",
+			pkgs:    []string{"jq"},
+			wantErr: nil,
+		},
+		{
+			name:    "not installed",
+			pkgs:    []string{"not-installed"},
+			wantErr: pkgmgr.ErrNotInstalled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mgr.Uninstall(ctx, tt.pkgs...)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBrewList(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "brew",
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	pkgs, err := mgr.List(ctx)
+
+	require.NoError(t, err)
+	require.Len(t, pkgs, 3)
+	require.Equal(t, "git", pkgs[0].Name)
+	require.Equal(t, "2.43.0", pkgs[0].Version)
+	require.Equal(t, "brew", pkgs[0].Source)
+	require.Equal(t, "homebrew/core", pkgs[0].Repository)
+	require.False(t, pkgs[0].Dependency)
+	require.Equal(t, "pcre2", pkgs[1].Name)
+	require.True(t, pkgs[1].Dependency)
+	require.Equal(t, "iterm2", pkgs[2].Name)
+	require.Equal(t, "3.4.23", pkgs[2].Version)
+	require.Equal(t, "homebrew/cask", pkgs[2].Repository)
+}
+
+func TestBrewOutdated(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "brew",
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	pkgs, err := mgr.Outdated(ctx)
+
+	require.NoError(t, err)
+	require.Equal(t, []pkgmgr.OutdatedPackage{
+		{Name: "git", Version: "2.43.0", Latest: "2.44.0"},
+		{Name: "iterm2", Version: "3.4.23", Latest: "3.5.0"},
+	}, pkgs)
+}
+
+func TestNewWithConfig(t *testing.T) {
+	tests := []struct {
+		name             string
+		cfg              *Config
+		expectedExecPath string
+	}{
+		{
+			name:             "nil config uses default",
+			cfg:              nil,
+			expectedExecPath: "brew",
+		},
+		{
+			name:             "empty config uses default",
+			cfg:              &Config{},
+			expectedExecPath: "brew",
+		},
+		{
+			name: "custom executable path",
+			cfg: &Config{
+				ExecutablePath: "/opt/homebrew/bin/brew",
+			},
+			expectedExecPath: "/opt/homebrew/bin/brew",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr := New(tt.cfg)
+
+			require.NotNil(t, mgr)
+			require.Equal(t, tt.expectedExecPath, mgr.execPath)
+			require.NotNil(t, mgr.execCommand)
+		})
+	}
+}
+
+func TestCustomExecutablePath(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "custom-brew",
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	err := mgr.Install(ctx, "test-pkg")
+
+	require.NoError(t, err)
+}
+
+func TestBrewDoesNotSwitchVersions(t *testing.T) {
+	var mgr pkgmgr.Manager = New(nil)
+
+	_, ok := mgr.(pkgmgr.VersionSwitcher)
+
+	require.False(t, ok)
+}
@@ -0,0 +1,41 @@
+package brew
+
+import "devctl/pkg/pkgmgr"
+
+func init() {
+	pkgmgr.Register(pkgmgr.Descriptor{
+		Type:      pkgmgr.ManagerTypeBrew,
+		Platforms: []pkgmgr.Platform{pkgmgr.PlatformDarwin, pkgmgr.PlatformLinux},
+		New: func(cfg pkgmgr.ManagerConfig) (pkgmgr.Manager, error) {
+			return New(&Config{ExecutablePath: cfg.ExecutablePath}), nil
+		},
+		InstallGuide: installGuide,
+	})
+}
+
+func installGuide(p pkgmgr.Platform) *pkgmgr.InstallGuide {
+	guide := &pkgmgr.InstallGuide{
+		ManagerType: pkgmgr.ManagerTypeBrew,
+		Platform:    string(p),
+		URL:         "https://brew.sh",
+		VerifyCmd:   "brew --version",
+	}
+
+	if p == pkgmgr.PlatformDarwin {
+		guide.Instructions = []string{
+			"Open Terminal",
+			"Run: /bin/bash -c \"$(curl -fsSL https://raw.githubusercontent.com/Homebrew/install/HEAD/install.sh)\"",
+			"Follow the on-screen instructions",
+			"Add Homebrew to your PATH as instructed",
+		}
+	} else {
+		guide.Instructions = []string{
+			"Open Terminal",
+			"Run: /bin/bash -c \"$(curl -fsSL https://raw.githubusercontent.com/Homebrew/install/HEAD/install.sh)\"",
+			"Follow the on-screen instructions",
+			"Add Homebrew to your PATH: eval \"$(/home/linuxbrew/.linuxbrew/bin/brew shellenv)\"",
+		}
+	}
+
+	return guide
+}
@@ -0,0 +1,66 @@
+package pkgmgr
+
+import (
+	"context"
+	"fmt"
+)
+
+// Prerequisite represents a requirement check for installation.
+type Prerequisite struct {
+	Name    string
+	Passed  bool
+	Message string
+}
+
+// InstallProgress represents the progress of an installation.
+type InstallProgress struct {
+	Stage   string // "preparing", "downloading", "installing", "verifying", "complete"
+	Message string
+	Percent int // 0-100, -1 for indeterminate
+}
+
+// Installer defines the interface for package manager installation.
+type Installer interface {
+	// CanAutoInstall checks if automatic installation is supported.
+	CanAutoInstall() (bool, error)
+
+	// GetPrerequisites returns the list of prerequisite checks.
+	GetPrerequisites() []Prerequisite
+
+	// GetInstallCommand returns the command that will be executed (for transparency).
+	GetInstallCommand() string
+
+	// Install executes the installation process.
+	// Sends progress updates through the progress channel.
+	Install(ctx context.Context, progress chan<- InstallProgress) error
+
+	// Verify checks if the installation was successful and returns the executable path.
+	Verify() (string, error)
+}
+
+// InstallGuide provides manual installation instructions for a package manager.
+type InstallGuide struct {
+	ManagerType  ManagerType
+	Platform     string
+	Instructions []string
+	URL          string
+	VerifyCmd    string
+}
+
+// InstallError represents an error that occurred during installation.
+type InstallError struct {
+	Manager string
+	Output  string
+	Err     error
+}
+
+func (e *InstallError) Error() string {
+	if e.Output != "" {
+		return fmt.Sprintf("failed to install %s: %v\nOutput: %s", e.Manager, e.Err, e.Output)
+	}
+	return fmt.Sprintf("failed to install %s: %v", e.Manager, e.Err)
+}
+
+func (e *InstallError) Unwrap() error {
+	return e.Err
+}
@@ -12,11 +12,21 @@ type Package struct {
 	Description string
 	// Source is the origin of the package (e.g., "scoop", "brew", "apt").
 	Source string
+	// Repository is the bucket, tap or repository the package was installed
+	// from (e.g., "main", "homebrew/core", "PSGallery").
+	// Empty if the package manager does not report it.
+	Repository string
+	// Dependency reports whether the package was only installed to satisfy
+	// another package rather than requested explicitly. Always false if the
+	// package manager does not report it.
+	Dependency bool
 }
 
 // Manager defines the interface for package management operations.
 type Manager interface {
-	// Install installs one or more packages by name.
+	// Install installs one or more packages by name. A name may request an
+	// exact version as "name@version"; the version is split off at the last
+	// "@" and may be empty, as in "node@20@".
 	Install(ctx context.Context, names ...string) error
 	// Uninstall uninstalls one or more packages by name.
 	Uninstall(ctx context.Context, names ...string) error
@@ -24,6 +34,55 @@ type Manager interface {
 	List(ctx context.Context) ([]Package, error)
 }
 
+// CommandDescriber is implemented by managers that can describe the command
+// line an operation would run without running it.
+type CommandDescriber interface {
+	// InstallCommand returns the command line Install would run for names.
+	InstallCommand(names ...string) []string
+	// UninstallCommand returns the command line Uninstall would run for names.
+	UninstallCommand(names ...string) []string
+}
+
+// Upgrader is implemented by managers that can upgrade installed packages
+// in place with their native update command.
+type Upgrader interface {
+	// Upgrade upgrades one or more installed packages to their latest version.
+	Upgrade(ctx context.Context, names ...string) error
+	// UpgradeCommand returns the command line Upgrade would run for names.
+	UpgradeCommand(names ...string) []string
+}
+
+// VersionSwitcher is implemented by managers that can move an installed
+// package from one version to another without uninstalling it first.
+type VersionSwitcher interface {
+	// SwitchVersion replaces version from of the installed package name with
+	// version to. It returns ErrUnsupported if the manager cannot make this
+	// switch natively, for example because to is not installed side by side.
+	SwitchVersion(ctx context.Context, name, from, to string) error
+	// SwitchVersionCommand returns the command line SwitchVersion would run.
+	SwitchVersionCommand(name, from, to string) []string
+}
+
+// OutdatedPackage is an installed package with a newer version available.
+type OutdatedPackage struct {
+	// Name is the name of the package.
+	Name string
+	// Version is the installed version of the package.
+	Version string
+	// Latest is the newest version available to the package manager.
+	Latest string
+	// Repository is the bucket, tap or repository of the installed package.
+	// Empty if the package manager does not report it.
+	Repository string
+}
+
+// OutdatedLister is implemented by managers that can report installed
+// packages with newer versions available.
+type OutdatedLister interface {
+	// Outdated returns the installed packages that can be upgraded.
+	Outdated(ctx context.Context) ([]OutdatedPackage, error)
+}
+
 type ManagerType string
 
 const (
@@ -2,6 +2,7 @@ package pkgmgr
 
 import (
 	"runtime"
+	"slices"
 )
 
 // Platform represents an operating system platform.
@@ -18,35 +19,12 @@ func GetCurrent() Platform {
 	return Platform(runtime.GOOS)
 }
 
-// GetSupportedManagers returns the list of package managers supported on the given platform.
+// GetSupportedManagers returns the list of registered package managers supported on the given platform.
 func GetSupportedManagers(p Platform) []ManagerType {
-	switch p {
-	case PlatformWindows:
-		return []ManagerType{
-			ManagerTypeScoop,
-			ManagerTypePwsh,
-		}
-	case PlatformDarwin:
-		return []ManagerType{
-			ManagerTypeBrew,
-		}
-	case PlatformLinux:
-		return []ManagerType{
-			ManagerTypeBrew,
-			ManagerTypeApt,
-		}
-	default:
-		return []ManagerType{}
-	}
+	return DefaultRegistry.Supported(p)
 }
 
 // IsManagerSupported checks if a package manager is supported on the given platform.
 func IsManagerSupported(mgr ManagerType, p Platform) bool {
-	supported := GetSupportedManagers(p)
-	for _, m := range supported {
-		if m == mgr {
-			return true
-		}
-	}
-	return false
+	return slices.Contains(GetSupportedManagers(p), mgr)
 }
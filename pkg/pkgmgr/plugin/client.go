@@ -0,0 +1,209 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"slices"
+
+	"devctl/pkg/pkgmgr"
+)
+
+// Config holds configuration for a plugin package manager.
+type Config struct {
+	// Type is the manager type served by the plugin.
+	Type pkgmgr.ManagerType
+	// ExecutablePath is the path to the plugin executable.
+	// If empty, defaults to "devctl-pkgmgr-<type>" (assumes it's in PATH).
+	ExecutablePath string
+}
+
+// Manager implements pkgmgr.Manager by delegating to a plugin executable.
+type Manager struct {
+	managerType pkgmgr.ManagerType
+	execPath    string
+	execCommand func(ctx context.Context, name string, arg ...string) *exec.Cmd
+}
+
+// New returns a new plugin Manager with the given configuration.
+func New(cfg *Config) *Manager {
+	m := &Manager{
+		execCommand: exec.CommandContext,
+	}
+	if cfg != nil {
+		m.managerType = cfg.Type
+		m.execPath = cfg.ExecutablePath
+	}
+	if m.execPath == "" {
+		m.execPath = ExecutablePrefix + string(m.managerType)
+	}
+	return m
+}
+
+// Install installs one or more packages through the plugin.
+func (m *Manager) Install(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	_, err := m.call(ctx, Request{Method: MethodInstall, Names: names})
+	return err
+}
+
+// Uninstall uninstalls one or more packages through the plugin.
+func (m *Manager) Uninstall(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	_, err := m.call(ctx, Request{Method: MethodUninstall, Names: names})
+	return err
+}
+
+// List returns the packages installed through the plugin.
+func (m *Manager) List(ctx context.Context) ([]pkgmgr.Package, error) {
+	resp, err := m.call(ctx, Request{Method: MethodList})
+	if err != nil {
+		return nil, err
+	}
+	packages := fromPackages(resp.Packages)
+	for i := range packages {
+		if packages[i].Source == "" {
+			packages[i].Source = string(m.managerType)
+		}
+	}
+	return packages, nil
+}
+
+// InstallCommand describes the plugin invocation that installs names.
+// The request itself is sent on stdin.
+func (m *Manager) InstallCommand(names ...string) []string {
+	return append([]string{m.execPath, string(MethodInstall)}, names...)
+}
+
+// UninstallCommand describes the plugin invocation that uninstalls names.
+// The request itself is sent on stdin.
+func (m *Manager) UninstallCommand(names ...string) []string {
+	return append([]string{m.execPath, string(MethodUninstall)}, names...)
+}
+
+// Capabilities returns the optional capabilities advertised by the plugin.
+func (m *Manager) Capabilities(ctx context.Context) ([]string, error) {
+	resp, err := m.call(ctx, Request{Method: MethodCapabilities})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Capabilities, nil
+}
+
+// WithCapabilities returns m as a pkgmgr.Manager that also implements the
+// optional interfaces of the advertised capabilities caps: pkgmgr.Upgrader
+// for CapabilityUpgrade and pkgmgr.OutdatedLister for CapabilityOutdated.
+func (m *Manager) WithCapabilities(caps []string) pkgmgr.Manager {
+	upgrade := slices.Contains(caps, CapabilityUpgrade)
+	outdated := slices.Contains(caps, CapabilityOutdated)
+	switch {
+	case upgrade && outdated:
+		return &upgradingOutdatedManager{m, upgrader{m}, outdatedLister{m}}
+	case upgrade:
+		return &upgradingManager{m, upgrader{m}}
+	case outdated:
+		return &outdatedManager{m, outdatedLister{m}}
+	}
+	return m
+}
+
+type upgradingManager struct {
+	*Manager
+	upgrader
+}
+
+type outdatedManager struct {
+	*Manager
+	outdatedLister
+}
+
+type upgradingOutdatedManager struct {
+	*Manager
+	upgrader
+	outdatedLister
+}
+
+// upgrader implements pkgmgr.Upgrader for plugins with CapabilityUpgrade.
+type upgrader struct {
+	m *Manager
+}
+
+// Upgrade upgrades one or more packages through the plugin.
+func (u upgrader) Upgrade(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	_, err := u.m.call(ctx, Request{Method: MethodUpgrade, Names: names})
+	return err
+}
+
+// UpgradeCommand describes the plugin invocation that upgrades names.
+// The request itself is sent on stdin.
+func (u upgrader) UpgradeCommand(names ...string) []string {
+	return append([]string{u.m.execPath, string(MethodUpgrade)}, names...)
+}
+
+// outdatedLister implements pkgmgr.OutdatedLister for plugins with
+// CapabilityOutdated.
+type outdatedLister struct {
+	m *Manager
+}
+
+// Outdated returns the packages with a newer version available through the
+// plugin.
+func (o outdatedLister) Outdated(ctx context.Context) ([]pkgmgr.OutdatedPackage, error) {
+	resp, err := o.m.call(ctx, Request{Method: MethodOutdated})
+	if err != nil {
+		return nil, err
+	}
+	return fromOutdatedPackages(resp.Packages), nil
+}
+
+// call runs the plugin with req on stdin and decodes its response.
+func (m *Manager) call(ctx context.Context, req Request) (*Response, error) {
+	req.ProtocolVersion = ProtocolVersion
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plugin request: %w", err)
+	}
+
+	cmd := m.execCommand(ctx, m.execPath)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	var resp Response
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		if runErr == nil {
+			runErr = fmt.Errorf("invalid plugin response: %w", err)
+		}
+		return nil, &pkgmgr.ExecutionError{
+			Cmd:    m.execPath + " " + string(req.Method),
+			Stderr: stderr.String(),
+			Err:    runErr,
+		}
+	}
+
+	if resp.Error != nil {
+		return nil, resp.Error.toError()
+	}
+	if resp.ProtocolVersion != ProtocolVersion {
+		return nil, fmt.Errorf("plugin %s speaks protocol version %d, expected %d", m.execPath, resp.ProtocolVersion, ProtocolVersion)
+	}
+	if runErr != nil {
+		return nil, &pkgmgr.ExecutionError{
+			Cmd:    m.execPath + " " + string(req.Method),
+			Stderr: stderr.String(),
+			Err:    runErr,
+		}
+	}
+	return &resp, nil
+}
@@ -0,0 +1,252 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"devctl/pkg/pkgmgr"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeManager is the pkgmgr.Manager served by the helper process plugin.
+type fakeManager struct{}
+
+func (fakeManager) Install(_ context.Context, names ...string) error {
+	for _, name := range names {
+		switch name {
+		case "already-installed":
+			return pkgmgr.ErrAlreadyInstalled
+		case "no-such-package":
+			return fmt.Errorf("artifact %q: %w", name, pkgmgr.ErrNotFound)
+		case "broken":
+			return fmt.Errorf("artifact store unavailable")
+		}
+	}
+	return nil
+}
+
+func (fakeManager) Uninstall(_ context.Context, names ...string) error {
+	for _, name := range names {
+		if name == "not-installed" {
+			return pkgmgr.ErrNotInstalled
+		}
+	}
+	return nil
+}
+
+func (fakeManager) List(context.Context) ([]pkgmgr.Package, error) {
+	return []pkgmgr.Package{
+		{Name: "build-tools", Version: "4.2.0", Description: "Company build tools"},
+		{Name: "vpn-client", Version: "1.0.3", Source: "artifacts"},
+	}, nil
+}
+
+func (fakeManager) Outdated(context.Context) ([]pkgmgr.OutdatedPackage, error) {
+	return []pkgmgr.OutdatedPackage{
+		{Name: "build-tools", Version: "4.2.0", Latest: "4.3.1"},
+	}, nil
+}
+
+func (fakeManager) Capabilities() []string {
+	return []string{CapabilityOutdated}
+}
+
+// TestHelperProcess isn't a real test. It's used as the plugin executable.
+func TestHelperProcess(_ *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	switch os.Getenv("FAKE_PLUGIN_MODE") {
+	case "garbage":
+		fmt.Println("not json")
+		os.Exit(3)
+	case "old-protocol":
+		fmt.Println(`{"protocolVersion": 0}`)
+		return
+	}
+
+	Main(fakeManager{})
+}
+
+// fakeExecCommand is a helper to mock exec.Command
+func fakeExecCommand(ctx context.Context, name string, arg ...string) *exec.Cmd {
+	cs := []string{"-test.run=TestHelperProcess", "--", name}
+	cs = append(cs, arg...)
+	cmd := exec.CommandContext(ctx, os.Args[0], cs...)
+	cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1")
+	return cmd
+}
+
+func newTestManager() *Manager {
+	return &Manager{
+		managerType: "artifacts",
+		execPath:    "devctl-pkgmgr-artifacts",
+		execCommand: fakeExecCommand,
+	}
+}
+
+func TestPluginInstall(t *testing.T) {
+	mgr := newTestManager()
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		pkgs    []string
+		wantErr error
+	}{
+		{
+			name:    "successful install",
+			pkgs:    []string{"build-tools@4.2.0"},
+			wantErr: nil,
+		},
+		{
+			name:    "already installed",
+			pkgs:    []string{"already-installed"},
+			wantErr: pkgmgr.ErrAlreadyInstalled,
+		},
+		{
+			name:    "not found",
+			pkgs:    []string{"no-such-package"},
+			wantErr: pkgmgr.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mgr.Install(ctx, tt.pkgs...)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPluginInstallInternalError(t *testing.T) {
+	mgr := newTestManager()
+
+	err := mgr.Install(context.Background(), "broken")
+
+	require.ErrorContains(t, err, "artifact store unavailable")
+}
+
+func TestPluginUninstall(t *testing.T) {
+	mgr := newTestManager()
+	ctx := context.Background()
+
+	require.NoError(t, mgr.Uninstall(ctx, "build-tools"))
+	require.ErrorIs(t, mgr.Uninstall(ctx, "not-installed"), pkgmgr.ErrNotInstalled)
+}
+
+func TestPluginList(t *testing.T) {
+	mgr := newTestManager()
+
+	pkgs, err := mgr.List(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []pkgmgr.Package{
+		{Name: "build-tools", Version: "4.2.0", Description: "Company build tools", Source: "artifacts"},
+		{Name: "vpn-client", Version: "1.0.3", Source: "artifacts"},
+	}, pkgs)
+}
+
+func TestPluginCapabilities(t *testing.T) {
+	mgr := newTestManager()
+
+	caps, err := mgr.Capabilities(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"outdated"}, caps)
+}
+
+func TestPluginWithCapabilities(t *testing.T) {
+	mgr := newTestManager()
+
+	_, ok := mgr.WithCapabilities(nil).(pkgmgr.Upgrader)
+	require.False(t, ok)
+	_, ok = mgr.WithCapabilities(nil).(pkgmgr.OutdatedLister)
+	require.False(t, ok)
+
+	_, ok = mgr.WithCapabilities([]string{CapabilityUpgrade}).(pkgmgr.Upgrader)
+	require.True(t, ok)
+	_, ok = mgr.WithCapabilities([]string{CapabilityUpgrade}).(pkgmgr.OutdatedLister)
+	require.False(t, ok)
+
+	both := mgr.WithCapabilities([]string{CapabilityOutdated, CapabilityUpgrade})
+	_, ok = both.(pkgmgr.Upgrader)
+	require.True(t, ok)
+	_, ok = both.(pkgmgr.OutdatedLister)
+	require.True(t, ok)
+	_, ok = both.(pkgmgr.CommandDescriber)
+	require.True(t, ok)
+	require.Equal(t, []string{"devctl-pkgmgr-artifacts", "upgrade", "build-tools"}, both.(pkgmgr.Upgrader).UpgradeCommand("build-tools"))
+}
+
+func TestPluginOutdated(t *testing.T) {
+	mgr := newTestManager().WithCapabilities([]string{CapabilityOutdated}).(pkgmgr.OutdatedLister)
+
+	pkgs, err := mgr.Outdated(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []pkgmgr.OutdatedPackage{
+		{Name: "build-tools", Version: "4.2.0", Latest: "4.3.1"},
+	}, pkgs)
+}
+
+func TestPluginInvalidResponse(t *testing.T) {
+	mgr := newTestManager()
+
+	t.Run("garbage output", func(t *testing.T) {
+		t.Setenv("FAKE_PLUGIN_MODE", "garbage")
+
+		_, err := mgr.List(context.Background())
+
+		var execErr *pkgmgr.ExecutionError
+		require.ErrorAs(t, err, &execErr)
+	})
+
+	t.Run("protocol version mismatch", func(t *testing.T) {
+		t.Setenv("FAKE_PLUGIN_MODE", "old-protocol")
+
+		_, err := mgr.List(context.Background())
+
+		require.ErrorContains(t, err, "protocol version 0")
+	})
+}
+
+func TestServeRejectsUnknownProtocolVersion(t *testing.T) {
+	var out strings.Builder
+
+	err := Serve(context.Background(), fakeManager{}, strings.NewReader(`{"protocolVersion": 99, "method": "list"}`), &out)
+
+	require.NoError(t, err)
+	require.Contains(t, out.String(), `"code":"unsupported_version"`)
+}
+
+func TestServeOutdatedUnsupported(t *testing.T) {
+	var out strings.Builder
+	mgr := struct{ pkgmgr.Manager }{fakeManager{}}
+
+	err := Serve(context.Background(), mgr, strings.NewReader(`{"protocolVersion": 1, "method": "outdated"}`), &out)
+
+	require.NoError(t, err)
+	require.Contains(t, out.String(), `"code":"unsupported"`)
+}
+
+func TestNewWithConfig(t *testing.T) {
+	mgr := New(&Config{Type: "artifacts"})
+	require.Equal(t, "devctl-pkgmgr-artifacts", mgr.execPath)
+
+	mgr = New(&Config{Type: "artifacts", ExecutablePath: "/opt/devctl/plugins/devctl-pkgmgr-artifacts"})
+	require.Equal(t, "/opt/devctl/plugins/devctl-pkgmgr-artifacts", mgr.execPath)
+	require.NotNil(t, mgr.execCommand)
+}
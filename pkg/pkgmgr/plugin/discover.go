@@ -0,0 +1,117 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"devctl/pkg/pkgmgr"
+)
+
+// Plugin is an external package manager found on disk.
+type Plugin struct {
+	Type pkgmgr.ManagerType
+	Path string
+	// Capabilities are the optional capabilities the plugin advertised when
+	// it was discovered.
+	Capabilities []string
+}
+
+// capabilitiesTimeout bounds the time a plugin may take to report its
+// capabilities during discovery.
+const capabilitiesTimeout = 5 * time.Second
+
+// Discover returns the plugins found in dirs followed by the directories in PATH.
+// When the same type is found more than once, the first one wins. Each plugin
+// is asked for its capabilities; plugins that fail to answer have none.
+func Discover(dirs ...string) []Plugin {
+	dirs = append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+
+	seen := map[pkgmgr.ManagerType]bool{}
+	var plugins []Plugin
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			t, ok := typeFromFileName(entry.Name())
+			if !ok || seen[t] || !isExecutable(dir, entry) {
+				continue
+			}
+			seen[t] = true
+			p := Plugin{
+				Type: t,
+				Path: filepath.Join(dir, entry.Name()),
+			}
+			p.Capabilities = capabilities(p)
+			plugins = append(plugins, p)
+		}
+	}
+	return plugins
+}
+
+// Descriptor returns a registry descriptor for the plugin.
+// Plugins are host-local, so they are only supported on the current platform.
+func (p Plugin) Descriptor() pkgmgr.Descriptor {
+	return pkgmgr.Descriptor{
+		Type:      p.Type,
+		Platforms: []pkgmgr.Platform{pkgmgr.GetCurrent()},
+		New: func(cfg pkgmgr.ManagerConfig) (pkgmgr.Manager, error) {
+			execPath := cfg.ExecutablePath
+			if execPath == "" {
+				execPath = p.Path
+			}
+			return New(&Config{Type: p.Type, ExecutablePath: execPath}).WithCapabilities(p.Capabilities), nil
+		},
+		LookPath: func() string {
+			return p.Path
+		},
+	}
+}
+
+func capabilities(p Plugin) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), capabilitiesTimeout)
+	defer cancel()
+
+	caps, err := New(&Config{Type: p.Type, ExecutablePath: p.Path}).Capabilities(ctx)
+	if err != nil {
+		return nil
+	}
+	return caps
+}
+
+func typeFromFileName(name string) (pkgmgr.ManagerType, bool) {
+	if !strings.HasPrefix(name, ExecutablePrefix) {
+		return "", false
+	}
+	name = strings.TrimPrefix(name, ExecutablePrefix)
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(name)
+		if !strings.EqualFold(ext, ".exe") {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, ext)
+	}
+	if name == "" {
+		return "", false
+	}
+	return pkgmgr.ManagerType(name), true
+}
+
+func isExecutable(dir string, entry os.DirEntry) bool {
+	info, err := os.Stat(filepath.Join(dir, entry.Name()))
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode().Perm()&0111 != 0
+}
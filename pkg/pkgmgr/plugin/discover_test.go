@@ -0,0 +1,79 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"devctl/pkg/pkgmgr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscover(t *testing.T) {
+	ext := ""
+	if runtime.GOOS == "windows" {
+		ext = ".exe"
+	}
+
+	pluginDir := t.TempDir()
+	pathDir := t.TempDir()
+	writeExecutable := func(dir, name string) string {
+		p := filepath.Join(dir, name+ext)
+		require.NoError(t, os.WriteFile(p, []byte("#!/bin/sh\n"), 0755))
+		return p
+	}
+
+	artifacts := writeExecutable(pluginDir, "devctl-pkgmgr-artifacts")
+	writeExecutable(pathDir, "devctl-pkgmgr-artifacts")
+	tools := writeExecutable(pathDir, "devctl-pkgmgr-tools")
+	writeExecutable(pathDir, "unrelated-tool")
+	writeExecutable(pathDir, "devctl-pkgmgr-")
+	require.NoError(t, os.Mkdir(filepath.Join(pathDir, "devctl-pkgmgr-dir"+ext), 0755))
+	t.Setenv("PATH", pathDir)
+
+	plugins := Discover(pluginDir, filepath.Join(pluginDir, "missing"))
+
+	require.Equal(t, []Plugin{
+		{Type: "artifacts", Path: artifacts},
+		{Type: "tools", Path: tools},
+	}, plugins)
+}
+
+func TestDiscoverCapabilities(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin is a shell script")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho '{\"protocolVersion\": 1, \"capabilities\": [\"upgrade\"]}'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "devctl-pkgmgr-artifacts"), []byte(script), 0755))
+	t.Setenv("PATH", "")
+
+	plugins := Discover(dir)
+
+	require.Len(t, plugins, 1)
+	require.Equal(t, []string{CapabilityUpgrade}, plugins[0].Capabilities)
+}
+
+func TestPluginDescriptor(t *testing.T) {
+	p := Plugin{Type: "artifacts", Path: "/opt/plugins/devctl-pkgmgr-artifacts"}
+
+	d := p.Descriptor()
+
+	require.Equal(t, pkgmgr.ManagerType("artifacts"), d.Type)
+	require.Equal(t, []pkgmgr.Platform{pkgmgr.GetCurrent()}, d.Platforms)
+	require.Equal(t, p.Path, d.LookPath())
+
+	mgr, err := d.New(pkgmgr.ManagerConfig{})
+	require.NoError(t, err)
+	require.Equal(t, p.Path, mgr.(*Manager).execPath)
+
+	p.Capabilities = []string{CapabilityOutdated}
+	mgr, err = p.Descriptor().New(pkgmgr.ManagerConfig{})
+	require.NoError(t, err)
+	_, ok := mgr.(pkgmgr.OutdatedLister)
+	require.True(t, ok)
+	_, ok = mgr.(pkgmgr.Upgrader)
+	require.False(t, ok)
+}
@@ -0,0 +1,186 @@
+// Package plugin implements external package manager plugins.
+//
+// A plugin is an executable named "devctl-pkgmgr-<type>" found on PATH or in
+// the plugins directory under devctl's data directory. devctl starts the
+// plugin once per operation, writes a single JSON Request to its stdin and
+// reads a single JSON Response from its stdout. Anything written to stderr is
+// reported to the user when the plugin fails.
+//
+// Plugin authors implement pkgmgr.Manager and call Main from their main function.
+package plugin
+
+import (
+	"errors"
+	"fmt"
+
+	"devctl/pkg/pkgmgr"
+)
+
+// ProtocolVersion is the version of the request/response protocol.
+// It is incremented on incompatible changes.
+const ProtocolVersion = 1
+
+// ExecutablePrefix is the file name prefix of plugin executables.
+const ExecutablePrefix = "devctl-pkgmgr-"
+
+// Method identifies the operation requested from a plugin.
+type Method string
+
+const (
+	// MethodCapabilities asks the plugin for its optional capabilities.
+	MethodCapabilities Method = "capabilities"
+	// MethodInstall installs the packages in Request.Names.
+	MethodInstall Method = "install"
+	// MethodUninstall uninstalls the packages in Request.Names.
+	MethodUninstall Method = "uninstall"
+	// MethodList lists the installed packages.
+	MethodList Method = "list"
+	// MethodOutdated lists the installed packages with a newer version
+	// available. Plugins that support it advertise CapabilityOutdated.
+	MethodOutdated Method = "outdated"
+	// MethodUpgrade upgrades the installed packages in Request.Names.
+	// Plugins that support it advertise CapabilityUpgrade.
+	MethodUpgrade Method = "upgrade"
+)
+
+const (
+	// CapabilityOutdated is advertised by plugins that implement MethodOutdated.
+	CapabilityOutdated = "outdated"
+	// CapabilityUpgrade is advertised by plugins that implement MethodUpgrade.
+	CapabilityUpgrade = "upgrade"
+)
+
+// Request is sent by devctl to a plugin on stdin.
+type Request struct {
+	ProtocolVersion int      `json:"protocolVersion"`
+	Method          Method   `json:"method"`
+	Names           []string `json:"names,omitempty"`
+}
+
+// Response is written by a plugin to stdout.
+type Response struct {
+	ProtocolVersion int       `json:"protocolVersion"`
+	Packages        []Package `json:"packages,omitempty"`
+	Capabilities    []string  `json:"capabilities,omitempty"`
+	Error           *Error    `json:"error,omitempty"`
+}
+
+// Package is the wire representation of pkgmgr.Package.
+type Package struct {
+	Name        string `json:"name"`
+	Version     string `json:"version,omitempty"`
+	Description string `json:"description,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Repository  string `json:"repository,omitempty"`
+	Dependency  bool   `json:"dependency,omitempty"`
+	// Latest is the newest available version. Only set by MethodOutdated.
+	Latest string `json:"latest,omitempty"`
+}
+
+// ErrorCode classifies plugin errors so they can be mapped to pkgmgr sentinel errors.
+type ErrorCode string
+
+const (
+	ErrorCodeNotFound           ErrorCode = "not_found"
+	ErrorCodeAlreadyInstalled   ErrorCode = "already_installed"
+	ErrorCodeNotInstalled       ErrorCode = "not_installed"
+	ErrorCodeUnsupported        ErrorCode = "unsupported"
+	ErrorCodeUnsupportedVersion ErrorCode = "unsupported_version"
+	ErrorCodeInternal           ErrorCode = "internal"
+)
+
+// Error is the wire representation of an error returned by a plugin.
+type Error struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message,omitempty"`
+}
+
+var codeErrors = map[ErrorCode]error{
+	ErrorCodeNotFound:         pkgmgr.ErrNotFound,
+	ErrorCodeAlreadyInstalled: pkgmgr.ErrAlreadyInstalled,
+	ErrorCodeNotInstalled:     pkgmgr.ErrNotInstalled,
+	ErrorCodeUnsupported:      pkgmgr.ErrUnsupported,
+}
+
+// toError converts a wire error into a Go error wrapping the matching pkgmgr sentinel.
+func (e *Error) toError() error {
+	if sentinel, ok := codeErrors[e.Code]; ok {
+		if e.Message == "" {
+			return sentinel
+		}
+		return fmt.Errorf("%w: %s", sentinel, e.Message)
+	}
+	if e.Message == "" {
+		return fmt.Errorf("plugin error: %s", e.Code)
+	}
+	return fmt.Errorf("plugin error: %s: %s", e.Code, e.Message)
+}
+
+// fromError converts a Go error into its wire representation.
+func fromError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	for code, sentinel := range codeErrors {
+		if errors.Is(err, sentinel) {
+			return &Error{Code: code, Message: err.Error()}
+		}
+	}
+	return &Error{Code: ErrorCodeInternal, Message: err.Error()}
+}
+
+func toPackages(pkgs []pkgmgr.Package) []Package {
+	result := make([]Package, 0, len(pkgs))
+	for _, p := range pkgs {
+		result = append(result, Package{
+			Name:        p.Name,
+			Version:     p.Version,
+			Description: p.Description,
+			Source:      p.Source,
+			Repository:  p.Repository,
+			Dependency:  p.Dependency,
+		})
+	}
+	return result
+}
+
+func fromPackages(pkgs []Package) []pkgmgr.Package {
+	result := make([]pkgmgr.Package, 0, len(pkgs))
+	for _, p := range pkgs {
+		result = append(result, pkgmgr.Package{
+			Name:        p.Name,
+			Version:     p.Version,
+			Description: p.Description,
+			Source:      p.Source,
+			Repository:  p.Repository,
+			Dependency:  p.Dependency,
+		})
+	}
+	return result
+}
+
+func toOutdatedPackages(pkgs []pkgmgr.OutdatedPackage) []Package {
+	result := make([]Package, 0, len(pkgs))
+	for _, p := range pkgs {
+		result = append(result, Package{
+			Name:       p.Name,
+			Version:    p.Version,
+			Latest:     p.Latest,
+			Repository: p.Repository,
+		})
+	}
+	return result
+}
+
+func fromOutdatedPackages(pkgs []Package) []pkgmgr.OutdatedPackage {
+	result := make([]pkgmgr.OutdatedPackage, 0, len(pkgs))
+	for _, p := range pkgs {
+		result = append(result, pkgmgr.OutdatedPackage{
+			Name:       p.Name,
+			Version:    p.Version,
+			Latest:     p.Latest,
+			Repository: p.Repository,
+		})
+	}
+	return result
+}
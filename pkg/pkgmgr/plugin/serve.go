@@ -0,0 +1,100 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"devctl/pkg/pkgmgr"
+)
+
+// Capable is implemented by managers that advertise optional capabilities.
+type Capable interface {
+	Capabilities() []string
+}
+
+// Serve reads a single Request from in, dispatches it to mgr and writes the
+// Response to out. Errors returned by mgr are sent to devctl in the response;
+// Serve itself only fails if the request cannot be read or the response cannot be written.
+func Serve(ctx context.Context, mgr pkgmgr.Manager, in io.Reader, out io.Writer) error {
+	var req Request
+	if err := json.NewDecoder(in).Decode(&req); err != nil {
+		return fmt.Errorf("failed to decode request: %w", err)
+	}
+
+	resp := handle(ctx, mgr, req)
+	resp.ProtocolVersion = ProtocolVersion
+
+	if err := json.NewEncoder(out).Encode(resp); err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+	return nil
+}
+
+func handle(ctx context.Context, mgr pkgmgr.Manager, req Request) Response {
+	if req.ProtocolVersion != ProtocolVersion {
+		return Response{Error: &Error{
+			Code:    ErrorCodeUnsupportedVersion,
+			Message: fmt.Sprintf("protocol version %d is not supported, expected %d", req.ProtocolVersion, ProtocolVersion),
+		}}
+	}
+
+	switch req.Method {
+	case MethodCapabilities:
+		var caps []string
+		if c, ok := mgr.(Capable); ok {
+			caps = c.Capabilities()
+		}
+		return Response{Capabilities: caps}
+	case MethodInstall:
+		return Response{Error: fromError(mgr.Install(ctx, req.Names...))}
+	case MethodUninstall:
+		return Response{Error: fromError(mgr.Uninstall(ctx, req.Names...))}
+	case MethodList:
+		pkgs, err := mgr.List(ctx)
+		if err != nil {
+			return Response{Error: fromError(err)}
+		}
+		return Response{Packages: toPackages(pkgs)}
+	case MethodUpgrade:
+		u, ok := mgr.(pkgmgr.Upgrader)
+		if !ok {
+			return Response{Error: fromError(pkgmgr.ErrUnsupported)}
+		}
+		return Response{Error: fromError(u.Upgrade(ctx, req.Names...))}
+	case MethodOutdated:
+		o, ok := mgr.(pkgmgr.OutdatedLister)
+		if !ok {
+			return Response{Error: fromError(pkgmgr.ErrUnsupported)}
+		}
+		pkgs, err := o.Outdated(ctx)
+		if err != nil {
+			return Response{Error: fromError(err)}
+		}
+		return Response{Packages: toOutdatedPackages(pkgs)}
+	default:
+		return Response{Error: &Error{
+			Code:    ErrorCodeUnsupported,
+			Message: fmt.Sprintf("unknown method %q", req.Method),
+		}}
+	}
+}
+
+// Main serves a single request on stdin/stdout and exits.
+// It is meant to be the whole body of a plugin's main function:
+//
+//	func main() {
+//		plugin.Main(&myManager{})
+//	}
+func Main(mgr pkgmgr.Manager) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := Serve(ctx, mgr, os.Stdin, os.Stdout)
+	stop()
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,290 @@
+package pwsh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"devctl/pkg/pkgmgr"
+	"devctl/pkg/version"
+)
+
+const (
+	// ScopeCurrentUser installs modules for the current user only.
+	ScopeCurrentUser = "CurrentUser"
+	// ScopeAllUsers installs modules for all users and requires elevation.
+	ScopeAllUsers = "AllUsers"
+)
+
+// listScript lists installed modules as a JSON array. Version is converted to
+// a string explicitly, otherwise ConvertTo-Json serializes System.Version as an object.
+const listScript = `Get-InstalledModule | ` +
+	`Select-Object Name, @{Name='Version'; Expression={$_.Version.ToString()}}, Description, Repository | ` +
+	`ConvertTo-Json -AsArray -Compress`
+
+// outdatedScript lists installed modules with the newest version Find-Module
+// finds in the registered repositories as a JSON array. Latest is null for
+// modules that are not found in any repository.
+const outdatedScript = `$installed = @(Get-InstalledModule); ` +
+	`if ($installed.Count -gt 0) { ` +
+	`$latest = @{}; ` +
+	`Find-Module -Name $installed.Name -ErrorAction SilentlyContinue | ForEach-Object { $latest[$_.Name] = $_.Version.ToString() }; ` +
+	`$installed | Select-Object Name, @{Name='Version'; Expression={$_.Version.ToString()}}, @{Name='Latest'; Expression={$latest[$_.Name]}}, Repository | ` +
+	`ConvertTo-Json -AsArray -Compress }`
+
+// Config holds configuration for the PowerShell module manager.
+type Config struct {
+	// ExecutablePath is the path to the pwsh executable.
+	// If empty, defaults to "pwsh" (assumes it's in PATH).
+	ExecutablePath string
+	// Scope is the installation scope passed to Install-Module.
+	// If empty, defaults to ScopeCurrentUser.
+	Scope string
+}
+
+// Manager implements pkgmgr.Manager for PowerShell modules using PowerShellGet.
+type Manager struct {
+	execPath    string
+	scope       string
+	execCommand func(ctx context.Context, name string, arg ...string) *exec.Cmd
+}
+
+// New returns a new PowerShell module Manager with the given configuration.
+// If cfg is nil or ExecutablePath is empty, defaults to "pwsh".
+func New(cfg *Config) *Manager {
+	execPath := "pwsh"
+	scope := ScopeCurrentUser
+	if cfg != nil {
+		if cfg.ExecutablePath != "" {
+			execPath = cfg.ExecutablePath
+		}
+		if cfg.Scope != "" {
+			scope = cfg.Scope
+		}
+	}
+	return &Manager{
+		execPath:    execPath,
+		scope:       scope,
+		execCommand: exec.CommandContext,
+	}
+}
+
+// Install installs one or more modules using Install-Module.
+// Names in the form "name@version" are pinned with -RequiredVersion.
+func (m *Manager) Install(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	_, errStr, err := m.run(ctx, m.InstallCommand(names...))
+	if err != nil {
+		if strings.Contains(errStr, "No match was found") {
+			return pkgmgr.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Uninstall uninstalls one or more modules using Uninstall-Module.
+func (m *Manager) Uninstall(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	_, errStr, err := m.run(ctx, m.UninstallCommand(names...))
+	if err != nil {
+		if strings.Contains(errStr, "No match was found") {
+			return pkgmgr.ErrNotInstalled
+		}
+		return err
+	}
+	return nil
+}
+
+// Upgrade updates one or more installed modules using Update-Module.
+func (m *Manager) Upgrade(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	_, errStr, err := m.run(ctx, m.UpgradeCommand(names...))
+	if err != nil {
+		if strings.Contains(errStr, "was not installed by using Install-Module") {
+			return pkgmgr.ErrNotInstalled
+		}
+		return err
+	}
+	return nil
+}
+
+// SwitchVersion installs version to of module name side by side with version
+// from and then uninstalls from. With $ErrorActionPreference set to 'Stop',
+// from is only uninstalled once to is installed.
+func (m *Manager) SwitchVersion(ctx context.Context, name, from, to string) error {
+	_, errStr, err := m.run(ctx, m.SwitchVersionCommand(name, from, to))
+	if err != nil {
+		if strings.Contains(errStr, "No match was found") {
+			return pkgmgr.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// InstallCommand returns the pwsh command line that installs names.
+func (m *Manager) InstallCommand(names ...string) []string {
+	stmts := make([]string, 0, len(names))
+	for _, name := range names {
+		module, ver := splitVersion(name)
+		stmt := fmt.Sprintf("Install-Module -Name %s -Scope %s -Force -AllowClobber", quote(module), m.scope)
+		if ver != "" {
+			stmt += " -RequiredVersion " + quote(ver)
+		}
+		stmts = append(stmts, stmt)
+	}
+	return m.commandLine(strings.Join(stmts, "; "))
+}
+
+// UninstallCommand returns the pwsh command line that uninstalls names.
+func (m *Manager) UninstallCommand(names ...string) []string {
+	stmts := make([]string, 0, len(names))
+	for _, name := range names {
+		module, ver := splitVersion(name)
+		stmt := fmt.Sprintf("Uninstall-Module -Name %s", quote(module))
+		if ver != "" {
+			stmt += " -RequiredVersion " + quote(ver)
+		} else {
+			stmt += " -AllVersions"
+		}
+		stmts = append(stmts, stmt)
+	}
+	return m.commandLine(strings.Join(stmts, "; "))
+}
+
+// UpgradeCommand returns the pwsh command line that updates names.
+func (m *Manager) UpgradeCommand(names ...string) []string {
+	stmts := make([]string, 0, len(names))
+	for _, name := range names {
+		module, _ := splitVersion(name)
+		stmts = append(stmts, fmt.Sprintf("Update-Module -Name %s -Force", quote(module)))
+	}
+	return m.commandLine(strings.Join(stmts, "; "))
+}
+
+// SwitchVersionCommand returns the pwsh command line that replaces version
+// from of module name with version to.
+func (m *Manager) SwitchVersionCommand(name, from, to string) []string {
+	stmt := fmt.Sprintf("Install-Module -Name %s -Scope %s -Force -AllowClobber -RequiredVersion %s", quote(name), m.scope, quote(to))
+	if from != "" {
+		stmt += fmt.Sprintf("; Uninstall-Module -Name %s -RequiredVersion %s", quote(name), quote(from))
+	}
+	return m.commandLine(stmt)
+}
+
+func (m *Manager) commandLine(script string) []string {
+	script = "$ErrorActionPreference = 'Stop'; $ProgressPreference = 'SilentlyContinue'; " + script
+	return []string{m.execPath, "-NoProfile", "-NonInteractive", "-Command", script}
+}
+
+type installedModule struct {
+	Name        string `json:"Name"`
+	Version     string `json:"Version"`
+	Description string `json:"Description"`
+	Repository  string `json:"Repository"`
+}
+
+// List returns the installed modules using Get-InstalledModule.
+func (m *Manager) List(ctx context.Context) ([]pkgmgr.Package, error) {
+	stdout, _, err := m.run(ctx, m.commandLine(listScript))
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []installedModule
+	if out := bytes.TrimSpace(stdout); len(out) > 0 {
+		if err := json.Unmarshal(out, &modules); err != nil {
+			return nil, err
+		}
+	}
+
+	packages := make([]pkgmgr.Package, 0, len(modules))
+	for _, mod := range modules {
+		packages = append(packages, pkgmgr.Package{
+			Name:        mod.Name,
+			Version:     mod.Version,
+			Description: mod.Description,
+			Source:      "pwsh",
+			Repository:  mod.Repository,
+		})
+	}
+
+	return packages, nil
+}
+
+type outdatedModule struct {
+	Name       string `json:"Name"`
+	Version    string `json:"Version"`
+	Latest     string `json:"Latest"`
+	Repository string `json:"Repository"`
+}
+
+// Outdated returns the installed modules with a newer version available by
+// comparing Get-InstalledModule with Find-Module.
+func (m *Manager) Outdated(ctx context.Context) ([]pkgmgr.OutdatedPackage, error) {
+	stdout, _, err := m.run(ctx, m.commandLine(outdatedScript))
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []outdatedModule
+	if out := bytes.TrimSpace(stdout); len(out) > 0 {
+		if err := json.Unmarshal(out, &modules); err != nil {
+			return nil, err
+		}
+	}
+
+	var packages []pkgmgr.OutdatedPackage
+	for _, mod := range modules {
+		if mod.Latest == "" || version.Compare(mod.Latest, mod.Version) <= 0 {
+			continue
+		}
+		packages = append(packages, pkgmgr.OutdatedPackage{
+			Name:       mod.Name,
+			Version:    mod.Version,
+			Latest:     mod.Latest,
+			Repository: mod.Repository,
+		})
+	}
+	return packages, nil
+}
+
+// run executes a pwsh command line and returns stdout and stderr.
+// Errors are returned as *pkgmgr.ExecutionError.
+func (m *Manager) run(ctx context.Context, cmdline []string) ([]byte, string, error) {
+	cmd := m.execCommand(ctx, cmdline[0], cmdline[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		errStr := stderr.String()
+		return nil, errStr, &pkgmgr.ExecutionError{
+			Cmd:    strings.Join(cmdline, " "),
+			Stderr: errStr,
+			Err:    err,
+		}
+	}
+	return stdout.Bytes(), stderr.String(), nil
+}
+
+func splitVersion(name string) (string, string) {
+	if i := strings.LastIndex(name, "@"); i > 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+// quote returns s as a single-quoted PowerShell string literal.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,290 @@
+package pwsh
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"devctl/pkg/pkgmgr"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHelperProcess isn't a real test. It's used to mock exec.Command.
+func TestHelperProcess(_ *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	args := os.Args
+	for i := range args {
+		if args[i] == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+
+	if len(args) == 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "No command\n")
+		os.Exit(2)
+	}
+
+	cmd, script := args[0], args[len(args)-1]
+
+	switch cmd {
+	case "pwsh", "C:\\Program Files\\PowerShell\\7\\pwsh.exe":
+		switch {
+		case strings.Contains(script, "Install-Module -Name 'NoSuchModule'"):
+			_, _ = fmt.Fprintf(os.Stderr, "Install-Module: No match was found for the specified search criteria and module name 'NoSuchModule'.\n")
+			os.Exit(1)
+		case strings.Contains(script, "Install-Module"):
+			fmt.Println("")
+		case strings.Contains(script, "Uninstall-Module -Name 'NotInstalled'"):
+			_, _ = fmt.Fprintf(os.Stderr, "Uninstall-Module: No match was found for the specified search criteria and module names 'NotInstalled'.\n")
+			os.Exit(1)
+		case strings.Contains(script, "Uninstall-Module"):
+			fmt.Println("")
+		case strings.Contains(script, "Find-Module"):
+			fmt.Println(`[{"Name":"PSReadLine","Version":"2.3.4","Latest":"2.3.5","Repository":"PSGallery"},{"Name":"posh-git","Version":"1.1.0","Latest":"1.1.0","Repository":"PSGallery"},{"Name":"LocalTools","Version":"0.1.0","Latest":null,"Repository":null}]`)
+		case strings.Contains(script, "Get-InstalledModule"):
+			if os.Getenv("FAKE_PWSH_EMPTY") == "1" {
+				return
+			}
+			fmt.Println(`[{"Name":"PSReadLine","Version":"2.3.4","Description":"Great command line editing in the PowerShell console host","Repository":"PSGallery"},{"Name":"posh-git","Version":"1.1.0","Description":"Provides prompt with Git status summary information","Repository":"PSGallery"}]`)
+		default:
+			_, _ = fmt.Fprintf(os.Stderr, "Unknown script %s\n", script)
+			os.Exit(1)
+		}
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "Unknown command %s\n", cmd)
+		os.Exit(1)
+	}
+}
+
+// fakeExecCommand is a helper to mock exec.Command
+func fakeExecCommand(ctx context.Context, name string, arg ...string) *exec.Cmd {
+	cs := []string{"-test.run=TestHelperProcess", "--", name}
+	cs = append(cs, arg...)
+	cmd := exec.CommandContext(ctx, os.Args[0], cs...)
+	cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1")
+	return cmd
+}
+
+func TestPwshInstall(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "pwsh",
+		scope:       ScopeCurrentUser,
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		pkgs    []string
+		wantErr error
+	}{
+		{
+			name:    "successful install",
+			pkgs:    []string{"posh-git"},
+			wantErr: nil,
+		},
+		{
+			name:    "pinned version",
+			pkgs:    []string{"PSReadLine@2.3.4"},
+			wantErr: nil,
+		},
+		{
+			name:    "unknown module",
+			pkgs:    []string{"NoSuchModule"},
+			wantErr: pkgmgr.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mgr.Install(ctx, tt.pkgs...)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPwshInstallScript(t *testing.T) {
+	var script string
+	mgr := &Manager{
+		execPath: "pwsh",
+		scope:    ScopeCurrentUser,
+		execCommand: func(ctx context.Context, name string, arg ...string) *exec.Cmd {
+			script = arg[len(arg)-1]
+			return fakeExecCommand(ctx, name, arg...)
+		},
+	}
+
+	err := mgr.Install(context.Background(), "Az@11.1.0", "it's-quoted")
+
+	require.NoError(t, err)
+	require.Contains(t, script, "Install-Module -Name 'Az' -Scope CurrentUser -Force -AllowClobber -RequiredVersion '11.1.0'")
+	require.True(t, strings.HasSuffix(script, "Install-Module -Name 'it''s-quoted' -Scope CurrentUser -Force -AllowClobber"))
+}
+
+func TestPwshSwitchVersion(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "pwsh",
+		scope:       ScopeCurrentUser,
+		execCommand: fakeExecCommand,
+	}
+
+	cmdline := mgr.SwitchVersionCommand("posh-git", "1.1.0", "1.0.0")
+
+	require.NoError(t, mgr.SwitchVersion(context.Background(), "posh-git", "1.1.0", "1.0.0"))
+	require.Contains(t, cmdline[len(cmdline)-1],
+		"Install-Module -Name 'posh-git' -Scope CurrentUser -Force -AllowClobber -RequiredVersion '1.0.0'; Uninstall-Module -Name 'posh-git' -RequiredVersion '1.1.0'")
+}
+
+func TestPwshUninstall(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "pwsh",
+		scope:       ScopeCurrentUser,
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		pkgs    []string
+		wantErr error
+	}{
+		{
+			name:    "successful uninstall",
+			pkgs:    []string{"posh-git"},
+			wantErr: nil,
+		},
+		{
+			name:    "not installed",
+			pkgs:    []string{"NotInstalled"},
+			wantErr: pkgmgr.ErrNotInstalled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mgr.Uninstall(ctx, tt.pkgs...)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPwshList(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "pwsh",
+		scope:       ScopeCurrentUser,
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	pkgs, err := mgr.List(ctx)
+
+	require.NoError(t, err)
+	require.Len(t, pkgs, 2)
+	require.Equal(t, "PSReadLine", pkgs[0].Name)
+	require.Equal(t, "2.3.4", pkgs[0].Version)
+	require.Equal(t, "pwsh", pkgs[0].Source)
+	require.Equal(t, "PSGallery", pkgs[0].Repository)
+}
+
+func TestPwshListEmpty(t *testing.T) {
+	t.Setenv("FAKE_PWSH_EMPTY", "1")
+	mgr := &Manager{
+		execPath:    "pwsh",
+		scope:       ScopeCurrentUser,
+		execCommand: fakeExecCommand,
+	}
+
+	pkgs, err := mgr.List(context.Background())
+
+	require.NoError(t, err)
+	require.Empty(t, pkgs)
+}
+
+func TestPwshOutdated(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "pwsh",
+		scope:       ScopeCurrentUser,
+		execCommand: fakeExecCommand,
+	}
+
+	pkgs, err := mgr.Outdated(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []pkgmgr.OutdatedPackage{
+		{Name: "PSReadLine", Version: "2.3.4", Latest: "2.3.5", Repository: "PSGallery"},
+	}, pkgs)
+}
+
+func TestNewWithConfig(t *testing.T) {
+	tests := []struct {
+		name             string
+		cfg              *Config
+		expectedExecPath string
+		expectedScope    string
+	}{
+		{
+			name:             "nil config uses default",
+			cfg:              nil,
+			expectedExecPath: "pwsh",
+			expectedScope:    ScopeCurrentUser,
+		},
+		{
+			name:             "empty config uses default",
+			cfg:              &Config{},
+			expectedExecPath: "pwsh",
+			expectedScope:    ScopeCurrentUser,
+		},
+		{
+			name: "custom executable path and scope",
+			cfg: &Config{
+				ExecutablePath: "C:\\Program Files\\PowerShell\\7\\pwsh.exe",
+				Scope:          ScopeAllUsers,
+			},
+			expectedExecPath: "C:\\Program Files\\PowerShell\\7\\pwsh.exe",
+			expectedScope:    ScopeAllUsers,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr := New(tt.cfg)
+
+			require.NotNil(t, mgr)
+			require.Equal(t, tt.expectedExecPath, mgr.execPath)
+			require.Equal(t, tt.expectedScope, mgr.scope)
+			require.NotNil(t, mgr.execCommand)
+		})
+	}
+}
+
+func TestRegisteredScope(t *testing.T) {
+	mgr, err := pkgmgr.NewManager(pkgmgr.ManagerTypePwsh, pkgmgr.ManagerConfig{ExecutablePath: "pwsh", Scope: ScopeAllUsers})
+	require.NoError(t, err)
+	require.Equal(t, ScopeAllUsers, mgr.(*Manager).scope)
+
+	mgr, err = pkgmgr.NewManager(pkgmgr.ManagerTypePwsh, pkgmgr.ManagerConfig{ExecutablePath: "pwsh"})
+	require.NoError(t, err)
+	require.Equal(t, ScopeCurrentUser, mgr.(*Manager).scope)
+
+	_, err = pkgmgr.NewManager(pkgmgr.ManagerTypePwsh, pkgmgr.ManagerConfig{ExecutablePath: "pwsh", Scope: "Machine"})
+	require.ErrorContains(t, err, `invalid scope "Machine"`)
+}
@@ -0,0 +1,38 @@
+package pwsh
+
+import (
+	"fmt"
+
+	"devctl/pkg/pkgmgr"
+)
+
+func init() {
+	pkgmgr.Register(pkgmgr.Descriptor{
+		Type:      pkgmgr.ManagerTypePwsh,
+		Platforms: []pkgmgr.Platform{pkgmgr.PlatformWindows},
+		New: func(cfg pkgmgr.ManagerConfig) (pkgmgr.Manager, error) {
+			switch cfg.Scope {
+			case "", ScopeCurrentUser, ScopeAllUsers:
+			default:
+				return nil, fmt.Errorf("invalid scope %q, use %s or %s", cfg.Scope, ScopeCurrentUser, ScopeAllUsers)
+			}
+			return New(&Config{ExecutablePath: cfg.ExecutablePath, Scope: cfg.Scope}), nil
+		},
+		InstallGuide: installGuide,
+	})
+}
+
+func installGuide(_ pkgmgr.Platform) *pkgmgr.InstallGuide {
+	return &pkgmgr.InstallGuide{
+		ManagerType: pkgmgr.ManagerTypePwsh,
+		Platform:    "windows",
+		Instructions: []string{
+			"Visit the PowerShell GitHub releases page",
+			"Download the latest .msi installer for Windows",
+			"Run the installer and follow the prompts",
+			"Restart your terminal after installation",
+		},
+		URL:       "https://github.com/PowerShell/PowerShell/releases",
+		VerifyCmd: "pwsh --version",
+	}
+}
@@ -0,0 +1,141 @@
+package pkgmgr
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ManagerConfig holds the user configuration of a package manager.
+type ManagerConfig struct {
+	Version        string `json:"version,omitempty" yaml:"version,omitempty" toml:"version,omitempty"`
+	ExecutablePath string `json:"executablePath,omitempty" yaml:"executablePath,omitempty" toml:"executablePath,omitempty"`
+	// DisableSudo runs the package manager without sudo when not running as
+	// root. Only used by apt.
+	DisableSudo bool `json:"disableSudo,omitempty" yaml:"disableSudo,omitempty" toml:"disableSudo,omitempty"`
+	// Scope is the installation scope, "CurrentUser" or "AllUsers".
+	// Only used by pwsh.
+	Scope string `json:"scope,omitempty" yaml:"scope,omitempty" toml:"scope,omitempty"`
+}
+
+// Descriptor describes a package manager backend.
+// Backends register a descriptor once, usually from an init function,
+// and are then resolved by type through a Registry.
+type Descriptor struct {
+	// Type is the unique identifier of the package manager.
+	Type ManagerType
+	// Platforms lists the platforms the package manager is supported on.
+	Platforms []Platform
+	// New creates a Manager from the user configuration.
+	New func(cfg ManagerConfig) (Manager, error)
+	// NewInstaller creates an Installer for the package manager itself.
+	// Optional: nil if the package manager cannot be installed automatically.
+	NewInstaller func() Installer
+	// InstallGuide returns manual installation instructions for the platform.
+	// Optional: nil if no guide is available.
+	InstallGuide func(p Platform) *InstallGuide
+	// LookPath returns the path of the package manager executable, or "" if
+	// it is not installed. Optional: nil to search PATH for an executable
+	// named after Type.
+	LookPath func() string
+}
+
+// Registry holds the known package manager backends.
+// It is safe for concurrent use.
+type Registry struct {
+	mu          sync.RWMutex
+	descriptors map[ManagerType]Descriptor
+	order       []ManagerType
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		descriptors: make(map[ManagerType]Descriptor),
+	}
+}
+
+// Register adds a backend to the registry.
+// Returns an error if the descriptor is incomplete or the type is already registered.
+func (r *Registry) Register(d Descriptor) error {
+	if d.Type == "" {
+		return fmt.Errorf("missing manager type")
+	}
+	if d.New == nil {
+		return fmt.Errorf("manager %s: missing constructor", d.Type)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.descriptors[d.Type]; ok {
+		return fmt.Errorf("manager %s already registered", d.Type)
+	}
+	r.descriptors[d.Type] = d
+	r.order = append(r.order, d.Type)
+	return nil
+}
+
+// Lookup returns the descriptor registered for the given type.
+func (r *Registry) Lookup(t ManagerType) (Descriptor, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.descriptors[t]
+	return d, ok
+}
+
+// Descriptors returns all registered descriptors in registration order.
+func (r *Registry) Descriptors() []Descriptor {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]Descriptor, 0, len(r.order))
+	for _, t := range r.order {
+		result = append(result, r.descriptors[t])
+	}
+	return result
+}
+
+// Supported returns the types of the backends supported on the given platform,
+// in registration order.
+func (r *Registry) Supported(p Platform) []ManagerType {
+	result := []ManagerType{}
+	for _, d := range r.Descriptors() {
+		if slices.Contains(d.Platforms, p) {
+			result = append(result, d.Type)
+		}
+	}
+	return result
+}
+
+// NewManager creates a Manager of the given type.
+// Returns ErrUnsupported if no backend is registered for the type.
+func (r *Registry) NewManager(t ManagerType, cfg ManagerConfig) (Manager, error) {
+	d, ok := r.Lookup(t)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, t)
+	}
+	return d.New(cfg)
+}
+
+// DefaultRegistry is the registry used by the package level functions.
+var DefaultRegistry = NewRegistry()
+
+// Register adds a backend to DefaultRegistry.
+// It panics if the descriptor cannot be registered, since that is a programming error.
+func Register(d Descriptor) {
+	if err := DefaultRegistry.Register(d); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the descriptor registered in DefaultRegistry for the given type.
+func Lookup(t ManagerType) (Descriptor, bool) {
+	return DefaultRegistry.Lookup(t)
+}
+
+// NewManager creates a Manager of the given type from DefaultRegistry.
+func NewManager(t ManagerType, cfg ManagerConfig) (Manager, error) {
+	return DefaultRegistry.NewManager(t, cfg)
+}
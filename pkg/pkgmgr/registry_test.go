@@ -0,0 +1,65 @@
+package pkgmgr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeManager struct {
+	cfg ManagerConfig
+}
+
+func (m *fakeManager) Install(context.Context, ...string) error   { return nil }
+func (m *fakeManager) Uninstall(context.Context, ...string) error { return nil }
+func (m *fakeManager) List(context.Context) ([]Package, error)    { return nil, nil }
+
+func newFakeDescriptor(t ManagerType, platforms ...Platform) Descriptor {
+	return Descriptor{
+		Type:      t,
+		Platforms: platforms,
+		New: func(cfg ManagerConfig) (Manager, error) {
+			return &fakeManager{cfg: cfg}, nil
+		},
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry()
+
+	require.NoError(t, r.Register(newFakeDescriptor("in-house", PlatformLinux)))
+	require.Error(t, r.Register(newFakeDescriptor("in-house", PlatformLinux)), "duplicate type")
+	require.Error(t, r.Register(newFakeDescriptor("", PlatformLinux)), "missing type")
+	require.Error(t, r.Register(Descriptor{Type: "no-constructor"}), "missing constructor")
+
+	d, ok := r.Lookup("in-house")
+	require.True(t, ok)
+	require.Equal(t, ManagerType("in-house"), d.Type)
+
+	_, ok = r.Lookup("unknown")
+	require.False(t, ok)
+}
+
+func TestRegistrySupported(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Register(newFakeDescriptor("b", PlatformLinux, PlatformDarwin)))
+	require.NoError(t, r.Register(newFakeDescriptor("a", PlatformLinux)))
+	require.NoError(t, r.Register(newFakeDescriptor("c", PlatformWindows)))
+
+	require.Equal(t, []ManagerType{"b", "a"}, r.Supported(PlatformLinux))
+	require.Equal(t, []ManagerType{"b"}, r.Supported(PlatformDarwin))
+	require.Equal(t, []ManagerType{}, r.Supported("plan9"))
+}
+
+func TestRegistryNewManager(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Register(newFakeDescriptor("in-house", PlatformLinux)))
+
+	mgr, err := r.NewManager("in-house", ManagerConfig{ExecutablePath: "/opt/bin/in-house"})
+	require.NoError(t, err)
+	require.Equal(t, "/opt/bin/in-house", mgr.(*fakeManager).cfg.ExecutablePath)
+
+	_, err = r.NewManager("unknown", ManagerConfig{})
+	require.ErrorIs(t, err, ErrUnsupported)
+}
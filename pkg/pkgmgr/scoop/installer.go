@@ -1,29 +1,31 @@
-package installer
+package scoop
 
 import (
 	"context"
-	"devctl/pkg/executil"
 	"errors"
 	"fmt"
 	"os/exec"
 	"runtime"
 	"strings"
+
+	"devctl/pkg/executil"
+	"devctl/pkg/pkgmgr"
 )
 
-// ScoopInstaller implements Installer for Scoop package manager.
-type ScoopInstaller struct {
+// Installer implements pkgmgr.Installer for the Scoop package manager.
+type Installer struct {
 	execCommand func(ctx context.Context, name string, arg ...string) *exec.Cmd
 }
 
-// NewScoopInstaller creates a new Scoop installer.
-func NewScoopInstaller() *ScoopInstaller {
-	return &ScoopInstaller{
+// NewInstaller creates a new Scoop installer.
+func NewInstaller() *Installer {
+	return &Installer{
 		execCommand: exec.CommandContext,
 	}
 }
 
 // CanAutoInstall checks if Scoop can be automatically installed.
-func (s *ScoopInstaller) CanAutoInstall() (bool, error) {
+func (s *Installer) CanAutoInstall() (bool, error) {
 	// Check if running on Windows
 	if runtime.GOOS != "windows" {
 		return false, errors.New("scoop is only available on Windows")
@@ -38,12 +40,12 @@ func (s *ScoopInstaller) CanAutoInstall() (bool, error) {
 }
 
 // GetPrerequisites returns the list of prerequisite checks.
-func (s *ScoopInstaller) GetPrerequisites() []Prerequisite {
-	prereqs := []Prerequisite{}
+func (s *Installer) GetPrerequisites() []pkgmgr.Prerequisite {
+	prereqs := []pkgmgr.Prerequisite{}
 
 	// Check PowerShell
 	psInstalled := executil.IsInstalled("powershell") || executil.IsInstalled("pwsh")
-	prereqs = append(prereqs, Prerequisite{
+	prereqs = append(prereqs, pkgmgr.Prerequisite{
 		Name:    "PowerShell 5.1+",
 		Passed:  psInstalled,
 		Message: "PowerShell is required to install Scoop",
@@ -53,14 +55,14 @@ func (s *ScoopInstaller) GetPrerequisites() []Prerequisite {
 }
 
 // GetInstallCommand returns the command that will be executed.
-func (s *ScoopInstaller) GetInstallCommand() string {
+func (s *Installer) GetInstallCommand() string {
 	return "Invoke-RestMethod -Uri https://get.scoop.sh | Invoke-Expression"
 }
 
 // Install executes the Scoop installation process.
-func (s *ScoopInstaller) Install(ctx context.Context, progress chan<- InstallProgress) error {
+func (s *Installer) Install(ctx context.Context, progress chan<- pkgmgr.InstallProgress) error {
 	// Step 1: Set execution policy
-	progress <- InstallProgress{
+	progress <- pkgmgr.InstallProgress{
 		Stage:   "preparing",
 		Message: "Setting PowerShell execution policy...",
 		Percent: 10,
@@ -75,7 +77,7 @@ func (s *ScoopInstaller) Install(ctx context.Context, progress chan<- InstallPro
 		"Set-ExecutionPolicy -ExecutionPolicy RemoteSigned -Scope CurrentUser -Force")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return &InstallError{
+		return &pkgmgr.InstallError{
 			Manager: "scoop",
 			Output:  string(output),
 			Err:     fmt.Errorf("failed to set execution policy: %w", err),
@@ -83,7 +85,7 @@ func (s *ScoopInstaller) Install(ctx context.Context, progress chan<- InstallPro
 	}
 
 	// Step 2: Download and execute installation script
-	progress <- InstallProgress{
+	progress <- pkgmgr.InstallProgress{
 		Stage:   "downloading",
 		Message: "Downloading Scoop installer...",
 		Percent: 30,
@@ -99,7 +101,7 @@ func (s *ScoopInstaller) Install(ctx context.Context, progress chan<- InstallPro
 		}
 	`
 
-	progress <- InstallProgress{
+	progress <- pkgmgr.InstallProgress{
 		Stage:   "installing",
 		Message: "Installing Scoop (this may take 1-2 minutes)...",
 		Percent: 50,
@@ -108,7 +110,7 @@ func (s *ScoopInstaller) Install(ctx context.Context, progress chan<- InstallPro
 	cmd = s.execCommand(ctx, psCmd, "-Command", installScript)
 	output, err = cmd.CombinedOutput()
 	if err != nil {
-		return &InstallError{
+		return &pkgmgr.InstallError{
 			Manager: "scoop",
 			Output:  string(output),
 			Err:     fmt.Errorf("installation failed: %w", err),
@@ -116,7 +118,7 @@ func (s *ScoopInstaller) Install(ctx context.Context, progress chan<- InstallPro
 	}
 
 	// Step 3: Verify installation
-	progress <- InstallProgress{
+	progress <- pkgmgr.InstallProgress{
 		Stage:   "verifying",
 		Message: "Verifying installation...",
 		Percent: 90,
@@ -124,14 +126,14 @@ func (s *ScoopInstaller) Install(ctx context.Context, progress chan<- InstallPro
 
 	path, err := s.Verify()
 	if err != nil {
-		return &InstallError{
+		return &pkgmgr.InstallError{
 			Manager: "scoop",
 			Output:  string(output),
 			Err:     fmt.Errorf("verification failed: %w", err),
 		}
 	}
 
-	progress <- InstallProgress{
+	progress <- pkgmgr.InstallProgress{
 		Stage:   "complete",
 		Message: fmt.Sprintf("Scoop installed successfully at: %s", path),
 		Percent: 100,
@@ -141,7 +143,7 @@ func (s *ScoopInstaller) Install(ctx context.Context, progress chan<- InstallPro
 }
 
 // Verify checks if Scoop is installed and returns its path.
-func (s *ScoopInstaller) Verify() (string, error) {
+func (s *Installer) Verify() (string, error) {
 	path := executil.LookPath("scoop")
 	if path == "" {
 		return "", errors.New("scoop executable not found in PATH")
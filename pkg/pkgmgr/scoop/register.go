@@ -0,0 +1,32 @@
+package scoop
+
+import "devctl/pkg/pkgmgr"
+
+func init() {
+	pkgmgr.Register(pkgmgr.Descriptor{
+		Type:      pkgmgr.ManagerTypeScoop,
+		Platforms: []pkgmgr.Platform{pkgmgr.PlatformWindows},
+		New: func(cfg pkgmgr.ManagerConfig) (pkgmgr.Manager, error) {
+			return New(&Config{ExecutablePath: cfg.ExecutablePath}), nil
+		},
+		NewInstaller: func() pkgmgr.Installer {
+			return NewInstaller()
+		},
+		InstallGuide: installGuide,
+	})
+}
+
+func installGuide(_ pkgmgr.Platform) *pkgmgr.InstallGuide {
+	return &pkgmgr.InstallGuide{
+		ManagerType: pkgmgr.ManagerTypeScoop,
+		Platform:    "windows",
+		Instructions: []string{
+			"Open PowerShell",
+			"Run: Set-ExecutionPolicy -ExecutionPolicy RemoteSigned -Scope CurrentUser",
+			"Run: Invoke-RestMethod -Uri https://get.scoop.sh | Invoke-Expression",
+			"Restart your terminal after installation",
+		},
+		URL:       "https://scoop.sh",
+		VerifyCmd: "scoop --version",
+	}
+}
@@ -1,9 +1,11 @@
 package scoop
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"os/exec"
 	"strings"
 
@@ -41,8 +43,8 @@ func (m *Manager) Install(ctx context.Context, names ...string) error {
 	if len(names) == 0 {
 		return nil
 	}
-	args := append([]string{"install"}, names...)
-	cmd := m.execCommand(ctx, m.execPath, args...)
+	cmdline := m.InstallCommand(names...)
+	cmd := m.execCommand(ctx, cmdline[0], cmdline[1:]...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
@@ -51,7 +53,7 @@ func (m *Manager) Install(ctx context.Context, names ...string) error {
 			return pkgmgr.ErrAlreadyInstalled
 		}
 		return &pkgmgr.ExecutionError{
-			Cmd:    m.execPath + " " + strings.Join(args, " "),
+			Cmd:    strings.Join(cmdline, " "),
 			Stderr: errStr,
 			Err:    err,
 		}
@@ -64,8 +66,8 @@ func (m *Manager) Uninstall(ctx context.Context, names ...string) error {
 	if len(names) == 0 {
 		return nil
 	}
-	args := append([]string{"uninstall"}, names...)
-	cmd := m.execCommand(ctx, m.execPath, args...)
+	cmdline := m.UninstallCommand(names...)
+	cmd := m.execCommand(ctx, cmdline[0], cmdline[1:]...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
@@ -74,7 +76,7 @@ func (m *Manager) Uninstall(ctx context.Context, names ...string) error {
 			return pkgmgr.ErrNotInstalled
 		}
 		return &pkgmgr.ExecutionError{
-			Cmd:    m.execPath + " " + strings.Join(args, " "),
+			Cmd:    strings.Join(cmdline, " "),
 			Stderr: errStr,
 			Err:    err,
 		}
@@ -82,11 +84,78 @@ func (m *Manager) Uninstall(ctx context.Context, names ...string) error {
 	return nil
 }
 
+// Upgrade updates one or more installed apps using scoop update.
+func (m *Manager) Upgrade(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	cmdline := m.UpgradeCommand(names...)
+	cmd := m.execCommand(ctx, cmdline[0], cmdline[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		errStr := stderr.String()
+		if strings.Contains(errStr, "is not installed") {
+			return pkgmgr.ErrNotInstalled
+		}
+		return &pkgmgr.ExecutionError{
+			Cmd:    strings.Join(cmdline, " "),
+			Stderr: errStr,
+			Err:    err,
+		}
+	}
+	return nil
+}
+
+// SwitchVersion switches name to version to using scoop reset. Scoop keeps
+// previous versions until 'scoop cleanup', so this only works for versions
+// that are still installed side by side; otherwise it returns pkgmgr.ErrUnsupported.
+func (m *Manager) SwitchVersion(ctx context.Context, name, from, to string) error {
+	cmdline := m.SwitchVersionCommand(name, from, to)
+	cmd := m.execCommand(ctx, cmdline[0], cmdline[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		errStr := stderr.String()
+		if strings.Contains(stdout.String()+errStr, "isn't installed") {
+			return fmt.Errorf("%w: %s %s is not installed side by side", pkgmgr.ErrUnsupported, name, to)
+		}
+		return &pkgmgr.ExecutionError{
+			Cmd:    strings.Join(cmdline, " "),
+			Stderr: errStr,
+			Err:    err,
+		}
+	}
+	return nil
+}
+
+// InstallCommand returns the scoop install command line for names.
+func (m *Manager) InstallCommand(names ...string) []string {
+	return append([]string{m.execPath, "install"}, names...)
+}
+
+// UninstallCommand returns the scoop uninstall command line for names.
+func (m *Manager) UninstallCommand(names ...string) []string {
+	return append([]string{m.execPath, "uninstall"}, names...)
+}
+
+// UpgradeCommand returns the scoop update command line for names.
+func (m *Manager) UpgradeCommand(names ...string) []string {
+	return append([]string{m.execPath, "update"}, names...)
+}
+
+// SwitchVersionCommand returns the scoop reset command line that switches name to version to.
+func (m *Manager) SwitchVersionCommand(name, _, to string) []string {
+	return []string{m.execPath, "reset", name + "@" + to}
+}
+
 type exportOutput struct {
 	Apps []struct {
 		Name        string `json:"name"`
 		Version     string `json:"version"`
 		Description string `json:"description"`
+		Source      string `json:"source"`
 	} `json:"apps"`
 }
 
@@ -116,8 +185,116 @@ func (m *Manager) List(ctx context.Context) ([]pkgmgr.Package, error) {
 			Version:     app.Version,
 			Description: app.Description,
 			Source:      "scoop",
+			Repository:  app.Source,
 		})
 	}
 
 	return packages, nil
 }
+
+// Outdated returns the installed apps with a newer version available using scoop status.
+func (m *Manager) Outdated(ctx context.Context) ([]pkgmgr.OutdatedPackage, error) {
+	cmd := m.execCommand(ctx, m.execPath, "status")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, &pkgmgr.ExecutionError{
+			Cmd:    m.execPath + " status",
+			Stderr: stderr.String(),
+			Err:    err,
+		}
+	}
+	return parseStatus(stdout.String()), nil
+}
+
+// parseStatus parses the table printed by scoop status:
+//
+//	Name Installed Version Latest Version Missing Dependencies Info
+//	---- ----------------- -------------- -------------------- ----
+//	git  2.43.0.windows.1  2.44.0.windows.1
+//
+// Columns are located by the dashes under the header. Apps without a latest
+// version, such as apps that are only missing dependencies, are skipped.
+func parseStatus(output string) []pkgmgr.OutdatedPackage {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), " \t\r"))
+	}
+
+	var packages []pkgmgr.OutdatedPackage
+	for i := 1; i < len(lines); i++ {
+		if !isRule(lines[i]) {
+			continue
+		}
+		columns := map[string][2]int{}
+		for _, span := range ruleSpans(lines[i]) {
+			columns[field(lines[i-1], span[0], span[1])] = span
+		}
+		name, okName := columns["Name"]
+		installed, okInstalled := columns["Installed Version"]
+		latest, okLatest := columns["Latest Version"]
+		if !okName || !okInstalled || !okLatest {
+			continue
+		}
+
+		for _, line := range lines[i+1:] {
+			if line == "" {
+				break
+			}
+			pkg := pkgmgr.OutdatedPackage{
+				Name:    field(line, name[0], installed[0]),
+				Version: field(line, installed[0], latest[0]),
+				Latest:  field(line, latest[0], latest[1]),
+			}
+			if pkg.Name == "" || pkg.Latest == "" {
+				continue
+			}
+			packages = append(packages, pkg)
+		}
+		break
+	}
+	return packages
+}
+
+// isRule reports whether line only consists of dashes and spaces.
+func isRule(line string) bool {
+	return strings.Contains(line, "-") && strings.Trim(line, "- ") == ""
+}
+
+// ruleSpans returns the start and end offsets of the runs of dashes in line.
+// The last span extends to the end of the line.
+func ruleSpans(line string) [][2]int {
+	var spans [][2]int
+	for i := 0; i < len(line); {
+		if line[i] != '-' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(line) && line[i] == '-' {
+			i++
+		}
+		spans = append(spans, [2]int{start, i})
+	}
+	for j := 0; j < len(spans)-1; j++ {
+		spans[j][1] = spans[j+1][0]
+	}
+	if len(spans) > 0 {
+		spans[len(spans)-1][1] = -1
+	}
+	return spans
+}
+
+// field returns the trimmed text of line between start and end.
+// An end of -1 means the end of the line.
+func field(line string, start, end int) string {
+	if start >= len(line) {
+		return ""
+	}
+	if end < 0 || end > len(line) {
+		end = len(line)
+	}
+	return strings.TrimSpace(line[start:end])
+}
@@ -56,7 +56,22 @@ func TestHelperProcess(_ *testing.T) {
 			fmt.Printf("Uninstalling '%s'...\n", pkg)
 		case "export":
 			// Scoop export returns JSON
-			fmt.Println(`{"apps": [{"name": "curl", "version": "8.5.0", "description": "Command line tool and library for transferring data with URLs"}]}`)
+			fmt.Println(`{"apps": [{"name": "curl", "version": "8.5.0", "description": "Command line tool and library for transferring data with URLs", "source": "main"}]}`)
+		case "update":
+			pkg := args[len(args)-1]
+			if pkg == "not-installed" {
+				_, _ = fmt.Fprintf(os.Stderr, "'not-installed' is not installed.\n")
+				os.Exit(1)
+			}
+			fmt.Printf("Updating '%s'...\n", pkg)
+		case "reset":
+			if args[len(args)-1] == "git@2.40.0" {
+				fmt.Printf("'git' (2.40.0) isn't installed.\n")
+				os.Exit(1)
+			}
+			fmt.Printf("Resetting %s.\n", args[len(args)-1])
+		case "status":
+			fmt.Print(statusOutput)
 		default:
 			_, _ = fmt.Fprintf(os.Stderr, "Unknown scoop subcommand %s\n", subcmd)
 			os.Exit(1)
@@ -67,6 +82,16 @@ func TestHelperProcess(_ *testing.T) {
 	}
 }
 
+const statusOutput = `Scoop is up to date.
+
+Name    Installed Version Latest Version   Missing Dependencies Info
+----    ----------------- --------------   -------------------- ----
+git     2.43.0.windows.1  2.44.0.windows.1
+python  3.12.1            3.12.2                                Held package
+nodejs  21.5.0                             7zip
+
+`
+
 // fakeExecCommand is a helper to mock exec.Command
 func fakeExecCommand(ctx context.Context, name string, arg ...string) *exec.Cmd {
 	cs := []string{"-test.run=TestHelperProcess", "--", name}
@@ -167,6 +192,74 @@ func TestScoopList(t *testing.T) {
 	require.Equal(t, "curl", pkgs[0].Name)
 	require.Equal(t, "8.5.0", pkgs[0].Version)
 	require.Equal(t, "scoop", pkgs[0].Source)
+	require.Equal(t, "main", pkgs[0].Repository)
+}
+
+func TestScoopUpgrade(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "scoop",
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	require.NoError(t, mgr.Upgrade(ctx, "git"))
+	require.ErrorIs(t, mgr.Upgrade(ctx, "not-installed"), pkgmgr.ErrNotInstalled)
+	require.Equal(t, []string{"scoop", "update", "git", "curl"}, mgr.UpgradeCommand("git", "curl"))
+}
+
+func TestScoopSwitchVersion(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "scoop",
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	require.NoError(t, mgr.SwitchVersion(ctx, "git", "2.44.0", "2.43.0"))
+	require.ErrorIs(t, mgr.SwitchVersion(ctx, "git", "2.44.0", "2.40.0"), pkgmgr.ErrUnsupported)
+	require.Equal(t, []string{"scoop", "reset", "git@2.43.0"}, mgr.SwitchVersionCommand("git", "2.44.0", "2.43.0"))
+}
+
+func TestScoopOutdated(t *testing.T) {
+	mgr := &Manager{
+		execPath:    "scoop",
+		execCommand: fakeExecCommand,
+	}
+	ctx := context.Background()
+
+	pkgs, err := mgr.Outdated(ctx)
+
+	require.NoError(t, err)
+	require.Equal(t, []pkgmgr.OutdatedPackage{
+		{Name: "git", Version: "2.43.0.windows.1", Latest: "2.44.0.windows.1"},
+		{Name: "python", Version: "3.12.1", Latest: "3.12.2"},
+	}, pkgs)
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []pkgmgr.OutdatedPackage
+	}{
+		{
+			name:   "everything up to date",
+			output: "Scoop is up to date.\nEverything is ok!\n",
+			want:   nil,
+		},
+		{
+			name: "last column",
+			output: "Name Installed Version Latest Version\r\n" +
+				"---- ----------------- --------------\r\n" +
+				"jq   1.6               1.7.1\r\n",
+			want: []pkgmgr.OutdatedPackage{{Name: "jq", Version: "1.6", Latest: "1.7.1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, parseStatus(tt.output))
+		})
+	}
 }
 
 func TestNewWithConfig(t *testing.T) {
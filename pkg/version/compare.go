@@ -1,9 +1,8 @@
 package version
 
 import (
+	"regexp"
 	"strings"
-
-	"golang.org/x/mod/semver"
 )
 
 // Normalize standardizes a version string by ensuring it has a "v" prefix.
@@ -20,9 +19,9 @@ func Normalize(v string) string {
 	return "v" + v
 }
 
-// Equal compares two version strings for equality.
-// It normalizes both versions before comparison using semver.Canonical.
-// Returns true if the versions are semantically equal, false otherwise.
+// Equal compares two version strings for equality using Compare.
+// Returns true if the versions are equal, false otherwise.
+// An empty version is only equal to another empty version.
 func Equal(v1, v2 string) bool {
 	if v1 == "" && v2 == "" {
 		return true
@@ -30,14 +29,18 @@ func Equal(v1, v2 string) bool {
 	if v1 == "" || v2 == "" {
 		return false
 	}
+	return Compare(v1, v2) == 0
+}
 
-	norm1 := Normalize(v1)
-	norm2 := Normalize(v2)
-
-	canon1 := semver.Canonical(norm1)
-	canon2 := semver.Canonical(norm2)
-
-	return canon1 == canon2
+// Match reports whether installed is the wanted version. Unlike Equal, an
+// epoch or revision that only one of them has is ignored, so "2.43.0"
+// matches both "1:2.43.0-1ubuntu7" and "2.43.0_1". An empty version only
+// matches another empty version.
+func Match(installed, wanted string) bool {
+	if installed == "" || wanted == "" {
+		return installed == wanted
+	}
+	return compareLoose(installed, wanted) == 0
 }
 
 // IsEmpty checks if a version string is empty.
@@ -45,3 +48,222 @@ func Equal(v1, v2 string) bool {
 func IsEmpty(v string) bool {
 	return v == ""
 }
+
+// IsValid reports whether v looks like a version that can be ordered,
+// that is it starts with a digit after an optional "v" prefix or epoch.
+func IsValid(v string) bool {
+	p := parse(v)
+	return p.upstream != "" && isDigit(p.upstream[0])
+}
+
+// Compare returns -1, 0 or 1 depending on whether a is older than, equal to
+// or newer than b. It understands the version schemes used by the supported
+// package managers:
+//
+//	semver         1.2.3, v1.2.3, 1.2.3-rc.1 (pre-releases sort first)
+//	partial        1.7 == 1.7.0
+//	four-part      2.43.0.windows.1, 23.01.0.1
+//	dates          2024.01.15, 20240115
+//	dpkg           1:2.34-1ubuntu1 (epoch and Debian revision), 1.0~rc1 < 1.0
+//	brew           8.5.0_1 (revision)
+//
+// A missing epoch is 0 and a missing revision sorts before any revision, so
+// "1:2.43.0-1ubuntu7" is newer than "2.43.0". Numbers are compared
+// numerically and other characters in dpkg order.
+func Compare(a, b string) int {
+	return compare(parse(a), parse(b))
+}
+
+// compareLoose is Compare, except that an epoch or revision that only one
+// version has is ignored. It is not a total order and only used to check
+// installed versions against wanted ones.
+func compareLoose(a, b string) int {
+	pa, pb := parse(a), parse(b)
+	if pa.hasEpoch != pb.hasEpoch {
+		pa.epoch, pb.epoch = "", ""
+	}
+	if (pa.revision == "") != (pb.revision == "") {
+		pa.revision, pb.revision = "", ""
+	}
+	return compare(pa, pb)
+}
+
+func compare(pa, pb parsed) int {
+	if c := compareNumbers(pa.epoch, pb.epoch); c != 0 {
+		return c
+	}
+	if c := compareParts(pa.upstream, pb.upstream); c != 0 {
+		return c
+	}
+	switch {
+	case pa.pre == "" && pb.pre != "":
+		return 1
+	case pa.pre != "" && pb.pre == "":
+		return -1
+	}
+	if c := compareParts(pa.pre, pb.pre); c != 0 {
+		return c
+	}
+	return compareParts(pa.revision, pb.revision)
+}
+
+type parsed struct {
+	epoch    string
+	hasEpoch bool
+	upstream string
+	pre      string
+	revision string
+}
+
+var (
+	epochPattern        = regexp.MustCompile(`^(\d+):`)
+	brewRevisionPattern = regexp.MustCompile(`_(\d+)$`)
+	semverBuildPattern  = regexp.MustCompile(`^\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?\+[0-9A-Za-z.-]+$`)
+)
+
+func parse(v string) parsed {
+	var p parsed
+	v = strings.TrimSpace(v)
+	if len(v) > 1 && (v[0] == 'v' || v[0] == 'V') && isDigit(v[1]) {
+		v = v[1:]
+	}
+
+	if m := epochPattern.FindStringSubmatch(v); m != nil {
+		p.epoch, p.hasEpoch = m[1], true
+		v = v[len(m[0]):]
+	}
+
+	// Semver build metadata does not take part in ordering. Other uses of
+	// "+", like "1.2+dfsg", are compared as regular characters.
+	if semverBuildPattern.MatchString(v) {
+		v = v[:strings.Index(v, "+")]
+	}
+
+	if m := brewRevisionPattern.FindStringSubmatchIndex(v); m != nil {
+		p.revision = v[m[2]:m[3]]
+		v = v[:m[0]]
+	}
+
+	// A suffix after the last "-" is a Debian revision if it starts with
+	// a digit ("2.43.0-1ubuntu7") and the rest has no pre-release. Otherwise
+	// everything from the first "-" followed by a non-digit is a pre-release
+	// ("1.0.0-rc.1", "1.0.0-beta-2").
+	if i := strings.LastIndex(v, "-"); i > 0 && i < len(v)-1 && isDigit(v[i+1]) && preRelease(v[:i]) < 0 {
+		if p.revision == "" {
+			p.revision = v[i+1:]
+		}
+		v = v[:i]
+	}
+	if i := preRelease(v); i >= 0 {
+		p.pre = v[i+1:]
+		v = v[:i]
+	}
+
+	p.upstream = v
+	return p
+}
+
+// preRelease returns the index of the "-" that starts the pre-release of v,
+// the first "-" followed by a non-digit, or -1.
+func preRelease(v string) int {
+	for i := 1; i < len(v)-1; i++ {
+		if v[i] == '-' && !isDigit(v[i+1]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// compareParts compares two version strings as alternating runs of
+// non-digits and digits, like dpkg. Missing trailing numbers count as zero.
+func compareParts(a, b string) int {
+	for a != "" || b != "" {
+		if isZeroTail(a) && isZeroTail(b) {
+			return 0
+		}
+
+		var sa, sb string
+		sa, a = splitRun(a, false)
+		sb, b = splitRun(b, false)
+		if c := compareStrings(sa, sb); c != 0 {
+			return c
+		}
+
+		var na, nb string
+		na, a = splitRun(a, true)
+		nb, b = splitRun(b, true)
+		if c := compareNumbers(na, nb); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// isZeroTail reports whether s only consists of separators and zeros,
+// such as ".0.0", so that "1.7" equals "1.7.0".
+func isZeroTail(s string) bool {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '0', s[i] == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func splitRun(s string, digits bool) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// compareStrings compares non-digit runs in dpkg order: "~" sorts before
+// everything including the end of the string, letters sort before other
+// characters.
+func compareStrings(a, b string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		oa, ob := 0, 0
+		if i < len(a) {
+			oa = order(a[i])
+		}
+		if i < len(b) {
+			ob = order(b[i])
+		}
+		if oa != ob {
+			if oa < ob {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func order(c byte) int {
+	switch {
+	case c == '~':
+		return -1
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return int(c)
+	default:
+		return int(c) + 256
+	}
+}
+
+func compareNumbers(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
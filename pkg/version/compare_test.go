@@ -112,6 +112,42 @@ func TestEqual(t *testing.T) {
 			v2:   "1.0.0",
 			want: false,
 		},
+		{
+			name: "different non-semver versions",
+			v1:   "22.01",
+			v2:   "23.01",
+			want: false,
+		},
+		{
+			name: "partial version equals full version",
+			v1:   "1.7",
+			v2:   "1.7.0",
+			want: true,
+		},
+		{
+			name: "brew revision differs from version without revision",
+			v1:   "8.5.0_1",
+			v2:   "8.5.0",
+			want: false,
+		},
+		{
+			name: "different brew revisions",
+			v1:   "8.5.0_1",
+			v2:   "8.5.0_2",
+			want: false,
+		},
+		{
+			name: "dpkg version differs from upstream version",
+			v1:   "1:2.43.0-1ubuntu7",
+			v2:   "2.43.0",
+			want: false,
+		},
+		{
+			name: "zero epoch equals missing epoch",
+			v1:   "0:2.43.0",
+			v2:   "2.43.0",
+			want: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -126,6 +162,35 @@ func TestEqual(t *testing.T) {
 	}
 }
 
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed string
+		wanted    string
+		want      bool
+	}{
+		{name: "same version", installed: "1.7.0", wanted: "1.7", want: true},
+		{name: "different version", installed: "1.7.1", wanted: "1.7", want: false},
+		{name: "brew revision ignored", installed: "8.5.0_1", wanted: "8.5.0", want: true},
+		{name: "different brew revisions", installed: "8.5.0_1", wanted: "8.5.0_2", want: false},
+		{name: "epoch and debian revision ignored", installed: "1:2.43.0-1ubuntu7", wanted: "2.43.0", want: true},
+		{name: "different epochs", installed: "1:2.43.0", wanted: "2:2.43.0", want: false},
+		{name: "empty versions", installed: "", wanted: "", want: true},
+		{name: "empty installed version", installed: "", wanted: "1.0.0", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// #given: 已安装版本和期望版本
+			// #when: 调用 Match 函数比较
+			got := Match(tt.installed, tt.wanted)
+
+			// #then: 忽略只有一方具有的 epoch 和修订号
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestIsEmpty(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -165,3 +230,120 @@ func TestIsEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want int
+	}{
+		// semver
+		{name: "equal", a: "1.0.0", b: "1.0.0", want: 0},
+		{name: "patch older", a: "1.0.0", b: "1.0.1", want: -1},
+		{name: "minor newer", a: "1.2.0", b: "1.1.9", want: 1},
+		{name: "major older", a: "1.9.9", b: "2.0.0", want: -1},
+		{name: "numeric not lexical", a: "1.10.0", b: "1.9.0", want: 1},
+		{name: "v prefix ignored", a: "v1.2.3", b: "1.2.3", want: 0},
+		{name: "upper case V prefix ignored", a: "V1.2.3", b: "1.2.3", want: 0},
+		{name: "surrounding whitespace ignored", a: " 1.2.3 ", b: "1.2.3", want: 0},
+		{name: "pre-release before release", a: "1.0.0-rc.1", b: "1.0.0", want: -1},
+		{name: "release after pre-release", a: "1.0.0", b: "1.0.0-beta", want: 1},
+		{name: "alpha before beta", a: "1.0.0-alpha", b: "1.0.0-beta", want: -1},
+		{name: "numeric pre-release parts", a: "1.0.0-beta.2", b: "1.0.0-beta.11", want: -1},
+		{name: "pre-release of newer version", a: "1.0.1-rc.1", b: "1.0.0", want: 1},
+		{name: "pre-release with dash before release", a: "1.0.0-beta-2", b: "1.0.0", want: -1},
+		{name: "pre-release with dash before release candidate", a: "1.0.0-alpha-1", b: "1.0.0-rc.1", want: -1},
+		{name: "numeric parts of pre-release with dash", a: "1.0.0-beta-2", b: "1.0.0-beta-11", want: -1},
+		{name: "pre-release with dash of newer version", a: "1.0.1-alpha-1", b: "1.0.0", want: 1},
+		{name: "build metadata ignored", a: "1.0.0+build.5", b: "1.0.0+build.6", want: 0},
+		{name: "build metadata ignored on pre-release", a: "1.0.0-rc.1+exp", b: "1.0.0-rc.1", want: 0},
+
+		// partial versions
+		{name: "missing patch is zero", a: "1.7", b: "1.7.0", want: 0},
+		{name: "missing minor and patch are zero", a: "2", b: "2.0.0", want: 0},
+		{name: "partial older", a: "1.7", b: "1.7.1", want: -1},
+		{name: "partial newer", a: "1.8", b: "1.7.9", want: 1},
+		{name: "two-part versions", a: "22.01", b: "23.01", want: -1},
+		{name: "leading zeros", a: "23.01", b: "23.1", want: 0},
+
+		// four-part versions
+		{name: "four-part equal", a: "1.2.3.4", b: "1.2.3.4", want: 0},
+		{name: "four-part older", a: "1.2.3.4", b: "1.2.3.10", want: -1},
+		{name: "four-part after three-part", a: "1.2.3.1", b: "1.2.3", want: 1},
+		{name: "four-part zero equals three-part", a: "1.2.3.0", b: "1.2.3", want: 0},
+		{name: "git for windows", a: "2.43.0.windows.1", b: "2.43.0.windows.2", want: -1},
+		{name: "git for windows after upstream", a: "2.43.0.windows.1", b: "2.43.0", want: 1},
+		{name: "git for windows before next upstream", a: "2.43.0.windows.1", b: "2.44.0", want: -1},
+
+		// date versions
+		{name: "dotted dates", a: "2024.01.15", b: "2024.02.01", want: -1},
+		{name: "dotted dates without leading zeros", a: "2024.1.16", b: "2024.01.15", want: 1},
+		{name: "compact dates", a: "20240115", b: "20231231", want: 1},
+		{name: "dashed dates", a: "2024-01-15", b: "2024-02-01", want: -1},
+		{name: "dashed dates same month", a: "2024-01-15", b: "2024-01-02", want: 1},
+
+		// dpkg
+		{name: "epoch wins", a: "1:1.0", b: "2:0.9", want: -1},
+		{name: "same epoch", a: "1:2.34-1ubuntu1", b: "1:2.35-1", want: -1},
+		{name: "missing epoch is 0", a: "1:2.43.0-1ubuntu7", b: "2.43.0", want: 1},
+		{name: "missing epoch is 0 for older upstream", a: "1:1.0", b: "2.0", want: 1},
+		{name: "debian revision", a: "2.43.0-1ubuntu7", b: "2.43.0-1ubuntu10", want: -1},
+		{name: "missing debian revision sorts first", a: "8.5.0-2ubuntu10.1", b: "8.5.0", want: 1},
+		{name: "upstream before revision", a: "8.4.0-9", b: "8.5.0-1", want: -1},
+		{name: "tilde before release", a: "1.0~rc1", b: "1.0", want: -1},
+		{name: "tilde before tilde", a: "1.0~~", b: "1.0~", want: -1},
+		{name: "plus after release", a: "1.2+dfsg", b: "1.2", want: 1},
+		{name: "letters before other characters", a: "1.0a", b: "1.0+", want: -1},
+
+		// brew
+		{name: "brew revision", a: "8.5.0_1", b: "8.5.0_2", want: -1},
+		{name: "brew revision numeric", a: "8.5.0_10", b: "8.5.0_9", want: 1},
+		{name: "missing brew revision sorts first", a: "8.5.0_1", b: "8.5.0", want: 1},
+		{name: "brew upstream before revision", a: "8.4.0_3", b: "8.5.0", want: -1},
+
+		// non-numeric versions
+		{name: "identical non-numeric", a: "nightly", b: "nightly", want: 0},
+		{name: "different non-numeric", a: "nightly", b: "stable", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// #given: 两个版本字符串
+			// #when: 调用 Compare 函数比较
+			got := Compare(tt.a, tt.b)
+			reversed := Compare(tt.b, tt.a)
+
+			// #then: 返回排序结果, 交换参数时结果相反
+			require.Equal(t, tt.want, got)
+			require.Equal(t, -tt.want, reversed)
+		})
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "semver", input: "1.2.3", want: true},
+		{name: "v prefix", input: "v1.2.3", want: true},
+		{name: "dpkg epoch", input: "1:2.34-1ubuntu1", want: true},
+		{name: "date", input: "2024.01.15", want: true},
+		{name: "empty", input: "", want: false},
+		{name: "word", input: "nightly", want: false},
+		{name: "v only", input: "v", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// #given: 一个版本字符串
+			// #when: 调用 IsValid 函数
+			got := IsValid(tt.input)
+
+			// #then: 返回是否可以排序
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
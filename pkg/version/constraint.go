@@ -0,0 +1,278 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Latest is the constraint that accepts any installed version.
+const Latest = "latest"
+
+// Constraint is a set of acceptable versions.
+//
+// Supported forms:
+//
+//	""  "latest"  "*"      any version
+//	"1.6.0"  "=1.6.0"      exactly that version
+//	"^1.6"                 >=1.6 <2 (>=0.6 <0.7 for ^0.6)
+//	"~1.6.3"               >=1.6.3 <1.7
+//	"1.6.x"  "1.6.*"       >=1.6 <1.7, wildcards only at the end
+//	">=2.40 <3"            all comparisons must hold, "," may separate them
+//	"^1.6 || ^2"           either side must hold
+type Constraint struct {
+	raw    string
+	groups [][]term
+}
+
+type term struct {
+	op      string
+	version string
+}
+
+// ParseConstraint parses a version constraint.
+func ParseConstraint(s string) (*Constraint, error) {
+	s = strings.TrimSpace(s)
+	c := &Constraint{raw: s}
+	if s == "" || s == Latest || s == "*" {
+		return c, nil
+	}
+
+	for _, alt := range strings.Split(s, "||") {
+		var group []term
+		for _, field := range splitTerms(alt) {
+			terms, err := parseTerm(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+			}
+			group = append(group, terms...)
+		}
+		if len(group) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q: empty alternative", s)
+		}
+		c.groups = append(c.groups, group)
+	}
+	return c, nil
+}
+
+// splitTerms splits a list of comparisons separated by whitespace or commas,
+// keeping an operator together with the version that follows it.
+func splitTerms(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	var terms []string
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if strings.Trim(f, "=<>!^~") == "" && i+1 < len(fields) {
+			f += fields[i+1]
+			i++
+		}
+		terms = append(terms, f)
+	}
+	return terms
+}
+
+// parseTerm parses a single comparison into one or more primitive terms.
+func parseTerm(s string) ([]term, error) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", "!=", "==", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(s, prefix) {
+			op = prefix
+			break
+		}
+	}
+	v := strings.TrimSpace(s[len(op):])
+	if v == "" {
+		return nil, fmt.Errorf("missing version after %q", op)
+	}
+
+	if i := wildcard(v); i >= 0 {
+		if op != "" && op != "=" && op != "==" {
+			return nil, fmt.Errorf("wildcard %q cannot be used with %q", v, op)
+		}
+		parts := strings.Split(release(v), ".")
+		for _, part := range parts[i:] {
+			if !isWildcard(part) {
+				return nil, fmt.Errorf("wildcard in %q must be followed by wildcards only", v)
+			}
+		}
+		if i == 0 {
+			return []term{{op: "*"}}, nil
+		}
+		return bounds(strings.Join(parts[:i], "."), i-1)
+	}
+
+	switch op {
+	case "^":
+		parts := strings.Split(release(v), ".")
+		// The first non-zero part may not change, ^0.0 behaves like ~0.0.
+		pos := len(parts) - 1
+		for i, p := range parts {
+			if p != "0" {
+				pos = i
+				break
+			}
+		}
+		return bounds(v, pos)
+	case "~":
+		parts := strings.Split(release(v), ".")
+		pos := 0
+		if len(parts) > 1 {
+			pos = 1
+		}
+		return bounds(v, pos)
+	case "==":
+		op = "="
+	}
+	return []term{{op: op, version: v}}, nil
+}
+
+// bounds returns the range from v up to, but excluding, the next version
+// with the part at pos incremented.
+func bounds(v string, pos int) ([]term, error) {
+	parts := strings.Split(release(v), ".")
+	if pos < 0 || pos >= len(parts) {
+		return nil, fmt.Errorf("invalid version %q", v)
+	}
+	n, err := strconv.Atoi(parts[pos])
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q", v)
+	}
+	upper := append(append([]string{}, parts[:pos]...), strconv.Itoa(n+1))
+	return []term{
+		{op: ">=", version: v},
+		{op: "<", version: strings.Join(upper, ".")},
+	}, nil
+}
+
+// wildcard returns the index of the first release part of v that is "x",
+// "X" or "*", or -1. Letters elsewhere in a version, as in "1.0~exp1" or
+// "2.0.0-linux-x64", are not wildcards.
+func wildcard(v string) int {
+	for i, part := range strings.Split(release(v), ".") {
+		if isWildcard(part) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isWildcard(part string) bool {
+	return part == "x" || part == "X" || part == "*"
+}
+
+// release returns v without "v" prefix, pre-release and build suffixes.
+func release(v string) string {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		return v[:i]
+	}
+	return v
+}
+
+// String returns the constraint as it was written.
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// IsAny reports whether the constraint accepts any version.
+func (c *Constraint) IsAny() bool {
+	return len(c.groups) == 0
+}
+
+// Exact returns the version of a constraint that accepts exactly one version.
+func (c *Constraint) Exact() (string, bool) {
+	if len(c.groups) != 1 || len(c.groups[0]) != 1 {
+		return "", false
+	}
+	t := c.groups[0][0]
+	if t.op != "" && t.op != "=" {
+		return "", false
+	}
+	return t.version, true
+}
+
+// OnlyOlder reports whether every version the constraint accepts is older
+// than v, so that no upgrade from v can satisfy it.
+func (c *Constraint) OnlyOlder(v string) bool {
+	if c.IsAny() || !IsValid(v) {
+		return false
+	}
+	for _, group := range c.groups {
+		older := false
+		for _, t := range group {
+			switch t.op {
+			case "<", "<=":
+				older = older || !t.check(v)
+			case "", "=":
+				older = older || compareLoose(v, t.version) > 0
+			}
+		}
+		if !older {
+			return false
+		}
+	}
+	return true
+}
+
+// Check reports whether v satisfies the constraint.
+func (c *Constraint) Check(v string) bool {
+	if c.IsAny() {
+		return true
+	}
+	for _, group := range c.groups {
+		ok := true
+		for _, t := range group {
+			if !t.check(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (t term) check(v string) bool {
+	if t.op == "*" {
+		return true
+	}
+
+	cmp := compareLoose(v, t.version)
+	equal := v == t.version || cmp == 0
+	switch t.op {
+	case "", "=":
+		return equal
+	case "!=":
+		return !equal
+	}
+
+	if !IsValid(v) || !IsValid(t.version) {
+		return false
+	}
+	switch t.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return false
+	}
+}
+
+// Satisfies reports whether the installed version v satisfies constraint.
+// A constraint that cannot be parsed only matches the identical version.
+func Satisfies(v, constraint string) bool {
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return v == constraint
+	}
+	return c.Check(v)
+}
@@ -0,0 +1,162 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{constraint: "", version: "1.0.0", want: true},
+		{constraint: "latest", version: "23.01", want: true},
+		{constraint: "*", version: "1:2.43.0-1ubuntu7", want: true},
+		{constraint: "1.7.1", version: "1.7.1", want: true},
+		{constraint: "1.7", version: "1.7.0", want: true},
+		{constraint: "=1.7.1", version: "1.7.2", want: false},
+		{constraint: "23.01", version: "23.01", want: true},
+		{constraint: "23.01", version: "22.01", want: false},
+		{constraint: "^1.6", version: "1.6.0", want: true},
+		{constraint: "^1.6", version: "1.7.1", want: true},
+		{constraint: "^1.6", version: "1.5.9", want: false},
+		{constraint: "^1.6", version: "2.0.0", want: false},
+		{constraint: "^0.6", version: "0.6.4", want: true},
+		{constraint: "^0.6", version: "0.7.0", want: false},
+		{constraint: "^0.0.3", version: "0.0.4", want: false},
+		{constraint: "~1.6.3", version: "1.6.9", want: true},
+		{constraint: "~1.6.3", version: "1.7.0", want: false},
+		{constraint: "~1", version: "1.9.0", want: true},
+		{constraint: "1.6.x", version: "1.6.2", want: true},
+		{constraint: "1.6.*", version: "1.7.0", want: false},
+		{constraint: "1.x", version: "1.99.0", want: true},
+		{constraint: "1.x.x", version: "1.99.0", want: true},
+		{constraint: ">=2.40 <3", version: "2.44.0", want: true},
+		{constraint: ">=2.40 <3", version: "2.39.1", want: false},
+		{constraint: ">=2.40 <3", version: "3.0.0", want: false},
+		{constraint: ">= 2.40, < 3", version: "2.40.0", want: true},
+		{constraint: ">1.0 <=1.2", version: "1.2.0", want: true},
+		{constraint: "!=1.2.0", version: "1.2.1", want: true},
+		{constraint: "!=1.2.0", version: "1.2.0", want: false},
+		{constraint: "^1.6 || ^3", version: "3.1.0", want: true},
+		{constraint: "^1.6 || ^3", version: "2.1.0", want: false},
+		{constraint: ">=2.40", version: "not-a-version", want: false},
+		{constraint: "1.2.3-1~exp1", version: "1.2.3-1~exp1", want: true},
+		{constraint: "1.2.3-1~exp1", version: "1.2.4", want: false},
+		{constraint: "2.1-0ubuntu1~xenial", version: "2.1-0ubuntu1~xenial", want: true},
+		{constraint: "2.1-0ubuntu1~xenial", version: "2.2", want: false},
+		{constraint: "2.0.0-linux-x64", version: "2.0.0-linux-x64", want: true},
+		{constraint: "2.0.0-linux-x64", version: "2.0.5", want: false},
+		{constraint: "1.0-a.b.x", version: "1.0-a.b.x", want: true},
+		{constraint: "1.0-a.b.x", version: "1.0.1", want: false},
+		{constraint: "v1.X", version: "1.4.0", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+" "+tt.version, func(t *testing.T) {
+			// #given: 一个版本约束
+			c, err := ParseConstraint(tt.constraint)
+			require.NoError(t, err)
+
+			// #when: 检查版本是否满足约束
+			got := c.Check(tt.version)
+
+			// #then: 返回是否满足
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseConstraintError(t *testing.T) {
+	tests := []string{
+		">=",
+		"^1.6 ||",
+		">=1.x",
+		"1.*.3",
+		"x.2",
+		"^latest",
+		"^v",
+		"~-1",
+	}
+
+	for _, constraint := range tests {
+		t.Run(constraint, func(t *testing.T) {
+			// #given: 一个无效的版本约束
+			// #when: 解析约束
+			_, err := ParseConstraint(constraint)
+
+			// #then: 返回错误
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestConstraintExact(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string
+		wantOK     bool
+	}{
+		{constraint: "1.7.1", want: "1.7.1", wantOK: true},
+		{constraint: "=1.7.1", want: "1.7.1", wantOK: true},
+		{constraint: "v1.7.1", want: "v1.7.1", wantOK: true},
+		{constraint: "1:2.43.0-1ubuntu7", want: "1:2.43.0-1ubuntu7", wantOK: true},
+		{constraint: "1.2.3-1~exp1", want: "1.2.3-1~exp1", wantOK: true},
+		{constraint: "2.0.0-linux-x64", want: "2.0.0-linux-x64", wantOK: true},
+		{constraint: "latest", wantOK: false},
+		{constraint: "", wantOK: false},
+		{constraint: "^1.6", wantOK: false},
+		{constraint: ">=1.6 <2", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			// #given: 一个版本约束
+			c, err := ParseConstraint(tt.constraint)
+			require.NoError(t, err)
+
+			// #when: 获取精确版本
+			got, ok := c.Exact()
+
+			// #then: 只有精确约束返回版本
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConstraintOnlyOlder(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{constraint: "<2", version: "3.0", want: true},
+		{constraint: "<2", version: "1.5", want: false},
+		{constraint: "^1.6", version: "2.0.0", want: true},
+		{constraint: "^1.6", version: "1.5.0", want: false},
+		{constraint: "~1.6.3", version: "1.7.0", want: true},
+		{constraint: ">=1.6", version: "2.0.0", want: false},
+		{constraint: "^1.6 || ^3", version: "2.0.0", want: false},
+		{constraint: "^1.6 || <=2.5", version: "3.0.0", want: true},
+		{constraint: "latest", version: "2.0.0", want: false},
+		{constraint: "<2", version: "nightly", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+" "+tt.version, func(t *testing.T) {
+			// #given: 一个版本约束
+			c, err := ParseConstraint(tt.constraint)
+			require.NoError(t, err)
+
+			// #when: 判断约束是否只接受更旧的版本
+			got := c.OnlyOlder(tt.version)
+
+			// #then: 只有所有可接受版本都更旧时返回 true
+			require.Equal(t, tt.want, got)
+		})
+	}
+}